@@ -4,28 +4,127 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path"
+	"syscall"
 
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/alert"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/daemon"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/export"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/retention"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/sampler"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/ui"
 )
 
 func main() {
 	cfg := config.FromFlags(os.Args[1:])
+	proj, err := export.NewProjector(cfg.JSONFields)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if _, err := cfg.Location(); err != nil {
+		fmt.Fprintf(os.Stderr, "-tz: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := alert.ParseQuietHours(cfg.QuietHours, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "-quiet-hours: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckLine(cfg.LineFormat, cfg.LineColor); err != nil {
+		fmt.Fprintf(os.Stderr, "-line-format: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := path.Match(cfg.Track, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "-track: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckNameMode(cfg.NameMode); err != nil {
+		fmt.Fprintf(os.Stderr, "-name: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckStates(cfg.States); err != nil {
+		fmt.Fprintf(os.Stderr, "-states: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckSidePanels(cfg.SidePanels); err != nil {
+		fmt.Fprintf(os.Stderr, "-side-panels: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckTab(cfg.Tab); err != nil {
+		fmt.Fprintf(os.Stderr, "-tab: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckPanels(cfg.Panels); err != nil {
+		fmt.Fprintf(os.Stderr, "-panels: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.WatchdogNice < 0 || cfg.WatchdogNice > 19 {
+		fmt.Fprintf(os.Stderr, "-watchdog-nice: %d out of range 0..19\n", cfg.WatchdogNice)
+		os.Exit(1)
+	}
+	if err := ui.CheckLayout(cfg.Layout); err != nil {
+		fmt.Fprintf(os.Stderr, "-layout: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.CheckKeys(cfg.Keys); err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Headless socket API
+	if cfg.Serve != "" {
+		if err := runServe(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Headless CSV mode
+	if cfg.CSV != "" {
+		if err := runCSV(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Plain-text snapshot
+	if cfg.Once || cfg.Line {
+		write := ui.WriteOnce
+		if cfg.Line {
+			write = ui.WriteLine
+		}
+		if err := runOnce(cfg, write); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// JSON/NDJSON modes
 	if cfg.JSON || cfg.JSONStream || !isTTY() {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		s := sampler.New(cfg.Interval)
+		s := sampler.FromConfig(cfg)
 		out := json.NewEncoder(os.Stdout)
 		for samp := range s.Stream(ctx) {
-			_ = out.Encode(samp)
+			if v, err := proj.Project(samp); err == nil {
+				_ = out.Encode(v)
+			}
 			if !cfg.JSONStream {
 				return
 			}
 		}
+		if err := s.Err(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -35,6 +134,54 @@ func main() {
 	}
 }
 
+// runCSV appends one row per sample to cfg.CSV until the process is interrupted.
+func runCSV(cfg config.Config) error {
+	w, err := export.NewCSVAppender(cfg.CSV)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	s := sampler.FromConfig(cfg)
+	for samp := range s.Stream(ctx) {
+		if err := w.Append(samp); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// runOnce prints the second sample with write: the first has no previous
+// reading to compute CPU and IO rates against.
+func runOnce(cfg config.Config, write func(io.Writer, config.Config, model.Sample) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	s := sampler.FromConfig(cfg)
+	first := true
+	for samp := range s.Stream(ctx) {
+		if first {
+			first = false
+			continue
+		}
+		return write(os.Stdout, cfg, samp)
+	}
+	return s.Err()
+}
+
+// runServe exposes samples on a Unix socket until SIGINT/SIGTERM/SIGHUP.
+func runServe(cfg config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	s := sampler.FromConfig(cfg)
+	ring := retention.New(cfg.RetentionMax, cfg.Retention)
+	if err := daemon.New(cfg.Serve, ring).Run(ctx, s.Stream(ctx)); err != nil {
+		return err
+	}
+	return s.Err()
+}
+
+// newSampler builds a sampler honoring the config's sampling options.
 // isTTY is a tiny check to avoid pulling in extra deps; good enough for now.
 func isTTY() bool {
 	fi, err := os.Stdout.Stat()
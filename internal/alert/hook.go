@@ -0,0 +1,105 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event describes a metric that just became critical.
+type Event struct {
+	Metric    string    `json:"metric"` // cpu|mem|swap|temp|watchdog:<pid>
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Hook dispatches events to a shell command and/or webhook, suppressing
+// repeats of the same metric within Debounce.
+type Hook struct {
+	Cmd      string // shell command; %s stands for the message ($SRPS_ALERT_MESSAGE)
+	Webhook  string // URL receiving the Event as a JSON POST
+	Debounce time.Duration
+
+	// Quiet suppresses events (and, in the TUI, bells) during quiet hours,
+	// except for the metrics in QuietAlways, matched on the part before any
+	// ":" (cpu, mem, swap, temp, fd, watchdog).
+	Quiet       QuietHours
+	QuietAlways []string
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func New(cmd, webhook string, debounce time.Duration) *Hook {
+	return &Hook{Cmd: cmd, Webhook: webhook, Debounce: debounce, last: make(map[string]time.Time)}
+}
+
+// Enabled reports whether any destination is configured.
+func (h *Hook) Enabled() bool { return h != nil && (h.Cmd != "" || h.Webhook != "") }
+
+// Fire sends ev in the background unless the metric fired within Debounce.
+// It reports whether the event was dispatched.
+func (h *Hook) Fire(ev Event) bool {
+	if !h.Enabled() || h.Quieted(ev.Metric, ev.Timestamp) {
+		return false
+	}
+	h.mu.Lock()
+	if t, ok := h.last[ev.Metric]; ok && ev.Timestamp.Sub(t) < h.Debounce {
+		h.mu.Unlock()
+		return false
+	}
+	h.last[ev.Metric] = ev.Timestamp
+	h.mu.Unlock()
+
+	go h.dispatch(ev)
+	return true
+}
+
+// Quieted reports whether an event for metric at t falls in quiet hours.
+func (h *Hook) Quieted(metric string, t time.Time) bool {
+	if h == nil || !h.Quiet.Contains(t) {
+		return false
+	}
+	kind, _, _ := strings.Cut(metric, ":")
+	for _, m := range h.QuietAlways {
+		if m == kind {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Hook) dispatch(ev Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if h.Cmd != "" {
+		// The message can hold process names, which their owners choose, so
+		// it never reaches the shell as text: %s expands the environment
+		// variable instead, e.g. notify-send "%s" runs
+		// notify-send "$SRPS_ALERT_MESSAGE".
+		cmd := exec.CommandContext(ctx, "sh", "-c", strings.ReplaceAll(h.Cmd, "%s", "$SRPS_ALERT_MESSAGE"))
+		cmd.Env = append(os.Environ(), "SRPS_ALERT_METRIC="+ev.Metric, "SRPS_ALERT_MESSAGE="+ev.Message)
+		_ = cmd.Run()
+	}
+	if h.Webhook != "" {
+		body, err := json.Marshal(ev)
+		if err != nil {
+			return
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Webhook, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package alert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily time-of-day window, e.g. 22:00-07:00, during which
+// alerts are suppressed. The zero value is never quiet.
+type QuietHours struct {
+	start, end time.Duration // since midnight; start > end wraps past midnight
+	loc        *time.Location
+	set        bool
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM" as wall-clock times in loc (the
+// sample's own zone when nil). An empty spec disables quiet hours.
+func ParseQuietHours(spec string, loc *time.Location) (QuietHours, error) {
+	if strings.TrimSpace(spec) == "" {
+		return QuietHours{}, nil
+	}
+	from, to, ok := strings.Cut(spec, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("%q: want HH:MM-HH:MM", spec)
+	}
+	start, err := clockOffset(from)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	end, err := clockOffset(to)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("%q: empty window", spec)
+	}
+	return QuietHours{start: start, end: end, loc: loc, set: true}, nil
+}
+
+func clockOffset(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q: want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls inside the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.set {
+		return false
+	}
+	if q.loc != nil {
+		t = t.In(q.loc)
+	}
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if q.start < q.end {
+		return tod >= q.start && tod < q.end
+	}
+	return tod >= q.start || tod < q.end
+}
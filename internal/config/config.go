@@ -3,6 +3,8 @@ package config
 import (
 	"flag"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,37 +12,661 @@ import (
 type Config struct {
 	Interval   time.Duration
 	Sort       string
+	Sort2      string // tiebreak sort key; "" = automatic
+	NameMode   string // CMD column shows cmdline|comm|exe
 	Filter     string
 	JSON       bool
 	JSONStream bool
+	Once       bool     // print vitals and a plain-text process table, then exit
+	Line       bool     // print a one-line status bar summary, then exit
+	JSONFields []string // top-level sample sections kept in JSON output; nil = all
+	CSV        string
+	Serve      string
 	EnableGPU  bool
 	EnableBatt bool
+	Columns    []string // process table columns, in display order
+	CmdWidth   int      // cap on the CMD column width; 0 = use the space available
+	MinCPU     float64  // hide processes below this CPU percent
+	MinMem     float64  // hide processes below this memory percent
+	NetStates  bool     // tally TCP/UDP sockets by state (walks /proc/net/tcp*)
+	TopN       int      // processes kept in the top list (0 = no cap)
+	ThrottledN int      // processes kept in the throttled list (0 = no cap)
+	Adaptive   bool     // back off the interval while the system is idle
+	Light      bool     // read FD counts and IO counters only for the listed processes
+	CPUNorm    bool     // show per-process CPU as a share of the whole machine
+	Minimal    bool     // start with a single maximized panel instead of the dashboard
+	SplitRatio float64  // dashboard right panel share of the width; 0 = automatic
+	Smooth     float64  // EWMA weight of the newest sample for displayed rates; 0 = raw
+	SIUnits    bool     // scale byte counts and rates by 1000 instead of 1024
+
+	// Thousands groups the digits of exact counts (PIDs, FDs, threads,
+	// inotify watches, ...) with the locale's separator; it widens the
+	// process table's count columns.
+	Thousands bool
+
+	// SparkGradient colors each sparkline bar by its value (green→red)
+	// instead of the metric's flat color.
+	SparkGradient bool
+
+	// Process visibility: KernelThreads lists kthreadd's children; States is
+	// all, active (hide sleeping/idle) or rd (only running/uninterruptible).
+	KernelThreads bool
+	States        string
+
+	// GPUInterval is how often GPU tools (nvidia-smi, rocm-smi,
+	// intel_gpu_top) are polled; EnableGPU=false never runs them.
+	GPUInterval time.Duration
+
+	// Jitter randomizes each sampling delay by up to ±Jitter so the samples
+	// don't alias with work that runs on the same period.
+	Jitter time.Duration
+
+	// ContainerNames resolves the container IDs in cgroup paths to names via
+	// docker's metadata and docker/podman ps; off, they show as short IDs.
+	ContainerNames bool
+
+	// Flash briefly highlights a dashboard value that moved by at least
+	// this percent between samples (of 100 for percentages, else of the
+	// larger reading); 0 turns it off. FlashFor is how long it stays lit.
+	Flash    float64
+	FlashFor time.Duration
+
+	// JSON file output (SRPS_SYSMONI_JSON_FILE): once it reaches JSONMaxMB
+	// megabytes it rotates to file.1, file.2, ... keeping JSONKeep of them,
+	// gzipped with JSONGzip. JSONMaxMB 0 lets it grow.
+	JSONMaxMB int
+	JSONKeep  int
+	JSONGzip  bool
+
+	// LifetimeCPU ranks the Hall of Shame by CPU time since process start
+	// rather than CPU accumulated while sysmoni has been running.
+	LifetimeCPU bool
+
+	// Alert hook: run AlertCmd (%s = message) and/or POST to AlertWebhook
+	// when a metric turns critical, at most once per AlertDebounce per metric.
+	AlertCmd        string
+	AlertWebhook    string
+	AlertDebounce   time.Duration
+	AlertHysteresis float64 // points (°C for temps) below the threshold a critical metric must fall to clear
+	Bell            int     // terminal bells per critical transition (0 = off)
+	StuckAlert      int     // processes in D sleep that count as a pileup (0 = off)
+
+	// QuietHours ("22:00-07:00", in the -tz zone) suppresses the alert hook
+	// and bells, but not the on-screen badges, except for the metrics in
+	// QuietAlways.
+	QuietHours  string
+	QuietAlways []string
+
+	// LineFormat is the -line template; LineColor (none, ansi, tmux or
+	// polybar) picks the markup for fields past their thresholds.
+	LineFormat string
+	LineColor  string
+
+	// Remote runs the TUI against `RemoteCmd -json-stream` on this ssh target
+	// instead of sampling locally.
+	Remote    string
+	RemoteCmd string
+
+	// Watchdog renices processes over WatchdogCPU percent for WatchdogSamples
+	// consecutive samples to WatchdogNice (plus ionice -c3 with WatchdogIONice).
+	// It only logs what it would do unless Enforce is set; Enforce implies Watchdog.
+	Watchdog        bool
+	Enforce         bool
+	WatchdogCPU     float64
+	WatchdogSamples int
+	WatchdogNice    int
+	WatchdogIONice  bool
+
+	// Protect lists process names (globs) and PIDs that bulk kill and the
+	// renice/ionice tips refuse to touch and the watchdog leaves alone;
+	// WatchdogExempt only shields processes from the watchdog.
+	Protect        []string
+	WatchdogExempt []string
+
+	// Retention keeps full samples for the daemon's history command and the
+	// Analysis tab: at most RetentionMax of them, spanning at most Retention.
+	Retention    time.Duration
+	RetentionMax int
+
+	// Redact masks credentials in process command lines wherever they are
+	// shown or exported; RedactKeys, when set, replaces the default names
+	// whose values are masked.
+	Redact     bool
+	RedactKeys []string
+
+	// Keys remaps TUI actions from "key_<action> = <keys>" config lines:
+	// action name -> the keys that trigger it.
+	Keys map[string][]string
+
+	// ConfirmQuit asks before q/Esc quits; Q and Ctrl+C always quit.
+	ConfirmQuit bool
+
+	// Device filters: glob patterns for block devices and network interfaces.
+	// Empty include means all; exclude wins.
+	DiskInclude []string
+	DiskExclude []string
+	NetInclude  []string
+	NetExclude  []string
+
+	// Startup view: Tab is dashboard|analysis|system|throughput; Panels, when
+	// set, lists the panels shown (io,gpu,battery,temps,inotify,cgroups).
+	// RememberView saves the view on quit to ViewPath and restores it on the
+	// next start.
+	Tab          string
+	Panels       []string
+	RememberView bool
+
+	// SidePanels picks the leader lists in the right panel's upper and lower
+	// slots (io,fd,threads,conn,dmem,majflt,blkio); nil means io,fd.
+	SidePanels []string
+
+	// Layout lists the dashboard rows top to bottom, each an ordered list of
+	// card names (cpu,mem,swap,net,disk,hardware,procs); nil means the
+	// built-in three rows.
+	Layout [][]string
+
+	// Focus mode follows one process tree above the process table: FocusPID,
+	// or the process whose name matches the Track glob. With Track, or with
+	// Reattach, it moves on to a new instance of the same name on exit.
+	FocusPID int
+	Track    string
+	Reattach bool
+
+	// Header clock: TZ is "" or local, UTC, or an IANA zone name such as
+	// Europe/Berlin; ShowDate prefixes the date.
+	TZ       string
+	ShowDate bool
+
+	// File is the config file used for loading and persisting UI choices.
+	File string
 }
 
+// DefaultProtect is the protected process list when none is configured.
+var DefaultProtect = []string{"sshd", "systemd", "init"}
+
+// DefaultLineFormat is the -line template when none is configured.
+const DefaultLineFormat = "CPU {cpu} MEM {mem} NET ↓{rx} ↑{tx} T {temp}"
+
+// DefaultColumns is the process table layout when none is configured.
+var DefaultColumns = []string{"cmd", "pid", "ni", "cpu", "mem", "read", "write", "fd", "conn"}
+
 func Default() Config {
 	return Config{
 		Interval:   time.Second,
 		Sort:       "cpu",
+		NameMode:   "cmdline",
 		Filter:     "",
 		JSON:       false,
 		JSONStream: false,
+		CSV:        "",
+		Serve:      "",
 		EnableGPU:  true,
 		EnableBatt: true,
+		Columns:    append([]string{}, DefaultColumns...),
+		TopN:       64,
+		ThrottledN: 32,
+		File:       FilePath(),
+		States:     "all",
+
+		AlertDebounce:   5 * time.Minute,
+		AlertHysteresis: 5,
+		StuckAlert:      5,
+		QuietAlways:     []string{"temp"},
+		RemoteCmd:       "sysmoni",
+
+		LineFormat: DefaultLineFormat,
+		LineColor:  "none",
+
+		ContainerNames: true,
+		GPUInterval:    2 * time.Second,
+
+		Flash:    20,
+		FlashFor: 600 * time.Millisecond,
+
+		JSONKeep: 5,
+
+		WatchdogCPU:     90,
+		WatchdogSamples: 5,
+		WatchdogNice:    10,
+
+		Protect: append([]string{}, DefaultProtect...),
+
+		Retention:    5 * time.Minute,
+		RetentionMax: 600,
+	}
+}
+
+// applyFile overlays values from the config file; flags and env still win.
+func (c *Config) applyFile(vals map[string]string) {
+	if v, ok := vals["interval"]; ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.Interval = d
+		}
+	}
+	if v, ok := vals["sort"]; ok && v != "" {
+		c.Sort = v
+	}
+	if v, ok := vals["sort2"]; ok {
+		c.Sort2 = v
+	}
+	if v, ok := vals["name"]; ok && v != "" {
+		c.NameMode = v
+	}
+	if v, ok := vals["gpu"]; ok {
+		c.EnableGPU = v != "0" && v != "false"
+	}
+	if v, ok := vals["gpu_interval"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			c.GPUInterval = d
+		}
+	}
+	if v, ok := vals["jitter"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			c.Jitter = d
+		}
+	}
+	if v, ok := vals["battery"]; ok {
+		c.EnableBatt = v != "0" && v != "false"
+	}
+	if v, ok := vals["columns"]; ok {
+		c.Columns = SplitList(v)
+	}
+	if v, ok := vals["tab"]; ok {
+		c.Tab = v
+	}
+	if v, ok := vals["panels"]; ok {
+		c.Panels = SplitList(v)
+	}
+	if v, ok := vals["side_panels"]; ok {
+		c.SidePanels = SplitList(v)
+	}
+	if v, ok := vals["layout"]; ok {
+		c.Layout = ParseLayout(v)
+	}
+	if v, ok := vals["track"]; ok {
+		c.Track = v
+	}
+	if v, ok := vals["reattach"]; ok {
+		c.Reattach = v == "1" || v == "true"
+	}
+	if v, ok := vals["remember_view"]; ok {
+		c.RememberView = v == "1" || v == "true"
+	}
+	if v, ok := vals["tz"]; ok {
+		c.TZ = v
+	}
+	if v, ok := vals["date"]; ok {
+		c.ShowDate = v == "1" || v == "true"
+	}
+	if v, ok := vals["cmd_width"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.CmdWidth = n
+		}
+	}
+	if v, ok := vals["json_fields"]; ok {
+		c.JSONFields = SplitList(v)
+	}
+	if v, ok := vals["disk_include"]; ok {
+		c.DiskInclude = SplitList(v)
+	}
+	if v, ok := vals["disk_exclude"]; ok {
+		c.DiskExclude = SplitList(v)
+	}
+	if v, ok := vals["net_include"]; ok {
+		c.NetInclude = SplitList(v)
+	}
+	if v, ok := vals["net_exclude"]; ok {
+		c.NetExclude = SplitList(v)
+	}
+	if v, ok := vals["adaptive"]; ok {
+		c.Adaptive = v == "1" || v == "true"
+	}
+	if v, ok := vals["light"]; ok {
+		c.Light = v == "1" || v == "true"
+	}
+	if v, ok := vals["si_units"]; ok {
+		c.SIUnits = v == "1" || v == "true"
+	}
+	if v, ok := vals["thousands"]; ok {
+		c.Thousands = v == "1" || v == "true"
+	}
+	if v, ok := vals["container_names"]; ok {
+		c.ContainerNames = v == "1" || v == "true"
+	}
+	if v, ok := vals["lifetime_cpu"]; ok {
+		c.LifetimeCPU = v == "1" || v == "true"
+	}
+	if v, ok := vals["split_ratio"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f < 1 {
+			c.SplitRatio = f
+		}
+	}
+	if v, ok := vals["smooth"]; ok {
+		c.Smooth, _ = strconv.ParseFloat(v, 64)
+	}
+	if v, ok := vals["flash"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+			c.Flash = f
+		}
+	}
+	if v, ok := vals["flash_for"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			c.FlashFor = d
+		}
+	}
+	if v, ok := vals["spark_gradient"]; ok {
+		c.SparkGradient = v == "1" || v == "true"
+	}
+	if v, ok := vals["minimal"]; ok {
+		c.Minimal = v == "1" || v == "true"
+	}
+	if v, ok := vals["cpu_norm"]; ok {
+		c.CPUNorm = v == "1" || v == "true"
+	}
+	if v, ok := vals["top_n"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.TopN = n
+		}
+	}
+	if v, ok := vals["throttled_n"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.ThrottledN = n
+		}
+	}
+	if v, ok := vals["confirm_quit"]; ok {
+		c.ConfirmQuit = v == "1" || v == "true"
+	}
+	if v, ok := vals["kthreads"]; ok {
+		c.KernelThreads = v == "1" || v == "true"
+	}
+	if v, ok := vals["states"]; ok && v != "" {
+		c.States = v
+	}
+	if v, ok := vals["netstates"]; ok {
+		c.NetStates = v == "1" || v == "true"
+	}
+	if v, ok := vals["min_cpu"]; ok {
+		c.MinCPU, _ = strconv.ParseFloat(v, 64)
+	}
+	if v, ok := vals["min_mem"]; ok {
+		c.MinMem, _ = strconv.ParseFloat(v, 64)
+	}
+	if v, ok := vals["alert_cmd"]; ok {
+		c.AlertCmd = v
+	}
+	if v, ok := vals["alert_webhook"]; ok {
+		c.AlertWebhook = v
+	}
+	if v, ok := vals["quiet_hours"]; ok {
+		c.QuietHours = v
+	}
+	if v, ok := vals["quiet_always"]; ok {
+		c.QuietAlways = SplitList(v)
+	}
+	if v, ok := vals["line_format"]; ok {
+		c.LineFormat = v
+	}
+	if v, ok := vals["line_color"]; ok {
+		c.LineColor = v
 	}
+	if v, ok := vals["alert_debounce"]; ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			c.AlertDebounce = d
+		}
+	}
+	if v, ok := vals["alert_hysteresis"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+			c.AlertHysteresis = f
+		}
+	}
+	if v, ok := vals["stuck_alert"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			c.StuckAlert = n
+		}
+	}
+	if v, ok := vals["retention"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			c.Retention = d
+		}
+	}
+	if v, ok := vals["retention_max"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			c.RetentionMax = n
+		}
+	}
+	if v, ok := vals["json_max_mb"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			c.JSONMaxMB = n
+		}
+	}
+	if v, ok := vals["json_keep"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			c.JSONKeep = n
+		}
+	}
+	if v, ok := vals["json_gzip"]; ok {
+		c.JSONGzip = v == "1" || v == "true"
+	}
+	if v, ok := vals["protect"]; ok {
+		c.Protect = SplitList(v)
+	}
+	if v, ok := vals["watchdog_exempt"]; ok {
+		c.WatchdogExempt = SplitList(v)
+	}
+	if v, ok := vals["redact"]; ok {
+		c.Redact = v == "1" || v == "true"
+	}
+	if v, ok := vals["redact_keys"]; ok {
+		c.RedactKeys = SplitList(v)
+	}
+	for k, v := range vals {
+		if action, ok := strings.CutPrefix(k, "key_"); ok {
+			if c.Keys == nil {
+				c.Keys = make(map[string][]string)
+			}
+			c.Keys[action] = strings.Fields(v)
+		}
+	}
+	if v, ok := vals["watchdog"]; ok {
+		c.Watchdog = v == "1" || v == "true"
+	}
+	if v, ok := vals["enforce"]; ok {
+		c.Enforce = v == "1" || v == "true"
+	}
+	if v, ok := vals["watchdog_cpu"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			c.WatchdogCPU = f
+		}
+	}
+	if v, ok := vals["watchdog_samples"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.WatchdogSamples = n
+		}
+	}
+	if v, ok := vals["watchdog_nice"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.WatchdogNice = n
+		}
+	}
+	if v, ok := vals["watchdog_ionice"]; ok {
+		c.WatchdogIONice = v == "1" || v == "true"
+	}
+	if v, ok := vals["bell"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.Bell = n
+		}
+	}
+}
+
+// Location resolves TZ; empty and "local" mean the system zone.
+func (c Config) Location() (*time.Location, error) {
+	if c.TZ == "" || strings.EqualFold(c.TZ, "local") {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.TZ)
+}
+
+// viewKeys are the config keys -remember-view saves; each matches its flag name.
+var viewKeys = []string{"tab", "sort", "sort2", "name", "panels"}
+
+// applyView restores the saved view state, except where a flag was given
+// explicitly on this run.
+func (c *Config) applyView(fs *flag.FlagSet) {
+	vals, err := LoadFile(ViewPath(c.File))
+	if err != nil {
+		return
+	}
+	fs.Visit(func(f *flag.Flag) { delete(vals, f.Name) })
+	view := make(map[string]string, len(viewKeys))
+	for _, k := range viewKeys {
+		if v, ok := vals[k]; ok {
+			view[k] = v
+		}
+	}
+	c.applyFile(view)
+}
+
+// SaveView writes the view state vals (keyed by viewKeys) to ViewPath.
+func SaveView(configFile string, vals map[string]string) error {
+	path := ViewPath(configFile)
+	if path == "" {
+		return nil
+	}
+	for _, k := range viewKeys {
+		if err := SaveValue(path, k, vals[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listFlag registers a comma-separated list flag that replaces *dst.
+func listFlag(fs *flag.FlagSet, dst *[]string, name, usage string) {
+	fs.Func(name, usage, func(v string) error {
+		*dst = SplitList(v)
+		return nil
+	})
+}
+
+// SplitList parses a comma-separated config value, dropping empty items.
+func SplitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ParseLayout parses a layout value: rows separated by "/", each a
+// comma-separated list of card names, e.g. "procs / cpu,mem,swap".
+func ParseLayout(v string) [][]string {
+	var rows [][]string
+	for _, row := range strings.Split(v, "/") {
+		rows = append(rows, SplitList(row))
+	}
+	if len(rows) == 1 && len(rows[0]) == 0 {
+		return nil
+	}
+	return rows
 }
 
 // FromFlags parses flags and environment overrides.
 func FromFlags(args []string) Config {
 	cfg := Default()
+	if vals, err := LoadFile(cfg.File); err == nil {
+		cfg.applyFile(vals)
+	}
 	fs := flag.NewFlagSet("sysmoni", flag.ContinueOnError)
 	fs.DurationVar(&cfg.Interval, "interval", cfg.Interval, "refresh interval")
-	fs.StringVar(&cfg.Sort, "sort", cfg.Sort, "sort column: cpu|mem")
+	fs.StringVar(&cfg.Sort, "sort", cfg.Sort, "sort column: cpu|mem|io|fd|conn|oom|dmem|dfd|majflt|blkio")
+	fs.StringVar(&cfg.Sort2, "sort2", cfg.Sort2, "secondary sort column used to break ties (default: mem for cpu, else cpu)")
+	fs.StringVar(&cfg.NameMode, "name", cfg.NameMode, "process name display: cmdline|comm|exe")
+	fs.StringVar(&cfg.Tab, "tab", cfg.Tab, "startup tab: dashboard|analysis|system|throughput")
+	listFlag(fs, &cfg.Panels, "panels", "comma-separated panels shown at startup: io,gpu,battery,temps,inotify,cgroups")
+	fs.IntVar(&cfg.FocusPID, "pid", cfg.FocusPID, "focus on this process and its children: totals, history and sparklines above the table")
+	fs.StringVar(&cfg.Track, "track", cfg.Track, "focus on the process whose name matches this glob, following new instances across restarts")
+	fs.BoolVar(&cfg.Reattach, "reattach", cfg.Reattach, "with -pid, follow a new process of the same name once it exits")
+	listFlag(fs, &cfg.SidePanels, "side-panels", "the two right-panel leader lists, e.g. io,threads (io,fd,threads,conn,dmem,majflt,blkio)")
+	fs.Func("layout", "dashboard rows separated by /, e.g. procs/cpu,mem,swap (cards: cpu,mem,swap,net,disk,hardware,procs)", func(v string) error {
+		cfg.Layout = ParseLayout(v)
+		return nil
+	})
+	fs.BoolVar(&cfg.RememberView, "remember-view", cfg.RememberView, "save tab, sort, name mode and panels on quit and restore them next time")
+	fs.StringVar(&cfg.TZ, "tz", cfg.TZ, "time zone of the header clock: local, UTC or an IANA name like Europe/Berlin")
+	fs.BoolVar(&cfg.ShowDate, "date", cfg.ShowDate, "show the date next to the header clock")
+	fs.IntVar(&cfg.CmdWidth, "cmd-width", cfg.CmdWidth, "maximum CMD column width in process tables (0 = fill)")
 	fs.StringVar(&cfg.Filter, "filter", cfg.Filter, "regex filter for process names")
 	fs.BoolVar(&cfg.JSON, "json", cfg.JSON, "output one-shot JSON and exit")
 	fs.BoolVar(&cfg.JSONStream, "json-stream", cfg.JSONStream, "stream NDJSON until interrupted")
-	fs.BoolVar(&cfg.EnableGPU, "gpu", cfg.EnableGPU, "enable GPU sampling")
-	fs.BoolVar(&cfg.EnableBatt, "battery", cfg.EnableBatt, "enable battery sampling")
+	fs.BoolVar(&cfg.Once, "once", cfg.Once, "print vitals and the top processes as plain text and exit, like top -bn1")
+	fs.BoolVar(&cfg.Line, "line", cfg.Line, "print a one-line summary for tmux/polybar status bars and exit")
+	fs.StringVar(&cfg.LineFormat, "line-format", cfg.LineFormat, "-line template; placeholders {cpu} {mem} {swap} {load} {rx} {tx} {read} {write} {temp}")
+	fs.StringVar(&cfg.LineColor, "line-color", cfg.LineColor, "color -line fields past their thresholds: none, ansi, tmux or polybar")
+	listFlag(fs, &cfg.JSONFields, "json-fields", "comma-separated sample sections to keep in JSON output, e.g. cpu,memory,top")
+	listFlag(fs, &cfg.DiskInclude, "disk-include", "comma-separated block device globs to show, e.g. 'nvme*n1,sd[a-z]'")
+	listFlag(fs, &cfg.DiskExclude, "disk-exclude", "comma-separated block device globs to hide, e.g. 'dm-*,ram*'")
+	listFlag(fs, &cfg.NetInclude, "net-include", "comma-separated network interface globs counted in NET totals")
+	listFlag(fs, &cfg.NetExclude, "net-exclude", "comma-separated network interface globs left out of NET totals, e.g. 'lo,veth*'")
+	fs.StringVar(&cfg.CSV, "csv", cfg.CSV, "append CSV rows to file until interrupted (headless)")
+	fs.StringVar(&cfg.Serve, "serve", cfg.Serve, "run headless and answer get/subscribe on this Unix socket")
+	fs.BoolVar(&cfg.EnableGPU, "gpu", cfg.EnableGPU, "enable GPU sampling (false never runs nvidia-smi/rocm-smi/intel_gpu_top)")
+	fs.DurationVar(&cfg.GPUInterval, "gpu-interval", cfg.GPUInterval, "how often to poll GPU tools, independent of -interval")
+	fs.DurationVar(&cfg.Jitter, "jitter", cfg.Jitter, "move each sample by a random amount up to ±this (at most half the interval)")
+	fs.BoolVar(&cfg.EnableBatt, "battery", cfg.EnableBatt, "enable battery sampling (false never reads the battery)")
+	fs.BoolVar(&cfg.Adaptive, "adaptive", cfg.Adaptive, "sample less often while the system is idle")
+	fs.BoolVar(&cfg.Light, "light", cfg.Light, "read FD counts and IO counters only for the top-N processes (cheaper on big hosts)")
+	fs.BoolVar(&cfg.ContainerNames, "container-names", cfg.ContainerNames, "resolve container IDs to names via docker metadata and docker/podman ps")
+	fs.BoolVar(&cfg.LifetimeCPU, "lifetime-cpu", cfg.LifetimeCPU, "rank the Hall of Shame by lifetime CPU time instead of since start")
+	fs.Float64Var(&cfg.Smooth, "smooth", cfg.Smooth, "smooth displayed net/disk/process IO rates with an EWMA of this weight (0-1, 0 = off)")
+	fs.Float64Var(&cfg.Flash, "flash", cfg.Flash, "flash dashboard values that change by at least this percent between samples (0 = off)")
+	fs.DurationVar(&cfg.FlashFor, "flash-for", cfg.FlashFor, "how long a changed value stays highlighted")
+	fs.BoolVar(&cfg.SIUnits, "si", cfg.SIUnits, "show byte counts and rates in SI units (1000) instead of binary (1024)")
+	fs.BoolVar(&cfg.Thousands, "thousands", cfg.Thousands, "group the digits of counts with the locale's thousands separator")
+	fs.BoolVar(&cfg.SparkGradient, "spark-gradient", cfg.SparkGradient, "color sparkline bars by value (green→red) instead of per metric")
+	fs.BoolVar(&cfg.Minimal, "minimal", cfg.Minimal, "single maximized panel for small terminals (tab cycles panels)")
+	fs.BoolVar(&cfg.CPUNorm, "cpu-norm", cfg.CPUNorm, "divide per-process CPU by core count (top's Irix-off mode)")
+	fs.IntVar(&cfg.TopN, "top-n", cfg.TopN, "number of top processes sampled (0 = all)")
+	fs.IntVar(&cfg.ThrottledN, "throttled-n", cfg.ThrottledN, "number of throttled processes kept (0 = all)")
+	fs.BoolVar(&cfg.ConfirmQuit, "confirm-quit", cfg.ConfirmQuit, "ask for confirmation before q quits")
+	fs.BoolVar(&cfg.NetStates, "netstates", cfg.NetStates, "tally TCP/UDP sockets by state")
+	fs.BoolVar(&cfg.KernelThreads, "kthreads", cfg.KernelThreads, "include kernel threads in the process lists")
+	fs.StringVar(&cfg.States, "states", cfg.States, "process states shown: all|active (hide sleeping/idle)|rd (running/uninterruptible only)")
+	fs.Float64Var(&cfg.MinCPU, "min-cpu", cfg.MinCPU, "hide processes using less CPU percent than this")
+	fs.Float64Var(&cfg.MinMem, "min-mem", cfg.MinMem, "hide processes using less memory percent than this")
+	fs.StringVar(&cfg.Remote, "remote", cfg.Remote, "watch another host: ssh target (user@host) running sysmoni")
+	fs.StringVar(&cfg.RemoteCmd, "remote-cmd", cfg.RemoteCmd, "sysmoni binary on the remote host")
+	fs.StringVar(&cfg.AlertCmd, "alert-cmd", cfg.AlertCmd, "shell command run when a metric turns critical (%s = message)")
+	fs.StringVar(&cfg.AlertWebhook, "alert-webhook", cfg.AlertWebhook, "URL that receives a JSON POST when a metric turns critical")
+	fs.BoolVar(&cfg.Redact, "redact", cfg.Redact, "mask passwords, tokens and keys in command lines (TUI, JSON, socket)")
+	listFlag(fs, &cfg.RedactKeys, "redact-keys", "comma-separated argument names whose values -redact masks (default password,secret,token,api_key,...)")
+	fs.DurationVar(&cfg.Retention, "retention", cfg.Retention, "keep full samples this far back for history queries and percentiles (0 = count limit only)")
+	fs.IntVar(&cfg.RetentionMax, "retention-max", cfg.RetentionMax, "maximum number of retained samples")
+	fs.IntVar(&cfg.JSONMaxMB, "json-max-mb", cfg.JSONMaxMB, "rotate the SRPS_SYSMONI_JSON_FILE output once it reaches this many MB (0 = never)")
+	fs.IntVar(&cfg.JSONKeep, "json-keep", cfg.JSONKeep, "rotated JSON output files to keep")
+	fs.BoolVar(&cfg.JSONGzip, "json-gzip", cfg.JSONGzip, "gzip rotated JSON output files")
+	fs.DurationVar(&cfg.AlertDebounce, "alert-debounce", cfg.AlertDebounce, "minimum time between alerts for the same metric")
+	fs.Float64Var(&cfg.AlertHysteresis, "alert-hysteresis", cfg.AlertHysteresis, "points (°C for temps) a critical metric must drop below its threshold to clear")
+	fs.StringVar(&cfg.QuietHours, "quiet-hours", cfg.QuietHours, "daily window, e.g. 22:00-07:00, when alerts and bells are suppressed (badges still show)")
+	listFlag(fs, &cfg.QuietAlways, "quiet-always", "comma-separated metrics that alert even in quiet hours: cpu,mem,swap,temp,fd,stuck,watchdog (default temp)")
+	fs.IntVar(&cfg.StuckAlert, "stuck-alert", cfg.StuckAlert, "alert when this many processes are in uninterruptible (D) sleep, e.g. on a hung NFS mount (0 = off)")
+	fs.IntVar(&cfg.Bell, "bell", cfg.Bell, "ring the terminal bell N times when a metric turns critical (0 = off)")
+	fs.BoolVar(&cfg.Watchdog, "watchdog", cfg.Watchdog, "log processes that hog the CPU and what -enforce would do about them (dry run)")
+	fs.BoolVar(&cfg.Enforce, "enforce", cfg.Enforce, "let the watchdog renice CPU hogs (implies -watchdog)")
+	fs.Float64Var(&cfg.WatchdogCPU, "watchdog-cpu", cfg.WatchdogCPU, "per-process CPU percent (100 = one core) the watchdog treats as hogging")
+	fs.IntVar(&cfg.WatchdogSamples, "watchdog-samples", cfg.WatchdogSamples, "consecutive samples over -watchdog-cpu before the watchdog acts")
+	fs.IntVar(&cfg.WatchdogNice, "watchdog-nice", cfg.WatchdogNice, "nice value the watchdog applies (0-19)")
+	listFlag(fs, &cfg.Protect, "protect", "comma-separated process names (globs) and PIDs that kill, renice and the watchdog never touch (default sshd,systemd,init)")
+	listFlag(fs, &cfg.WatchdogExempt, "watchdog-exempt", "comma-separated process names (globs) and PIDs the watchdog leaves alone")
+	fs.BoolVar(&cfg.WatchdogIONice, "watchdog-ionice", cfg.WatchdogIONice, "also move throttled processes to the idle IO class (ionice -c3)")
 	_ = fs.Parse(args)
+	if cfg.Enforce {
+		cfg.Watchdog = true
+	}
+	if cfg.RememberView {
+		cfg.applyView(fs)
+	}
 
 	if v := os.Getenv("SRPS_SYSMONI_INTERVAL"); v != "" {
 		if parsed, err := time.ParseDuration(v); err == nil {
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FilePath returns the sysmoni config file location: SRPS_SYSMONI_CONFIG if
+// set, else $XDG_CONFIG_HOME/sysmoni/sysmoni.conf (~/.config fallback).
+func FilePath() string {
+	if v := os.Getenv("SRPS_SYSMONI_CONFIG"); v != "" {
+		return v
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "sysmoni", "sysmoni.conf")
+}
+
+// ViewPath is where -remember-view keeps the last view: view.conf next to
+// the config file, so saved state never rewrites hand-edited settings.
+func ViewPath(configFile string) string {
+	if configFile == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(configFile), "view.conf")
+}
+
+// FilterHistoryPath is where the TUI keeps recently applied / filters:
+// filter_history next to the config file.
+func FilterHistoryPath(configFile string) string {
+	if configFile == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(configFile), "filter_history")
+}
+
+// LoadLines reads the non-blank lines of path. A missing file yields nil.
+func LoadLines(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// SaveLines replaces path with lines, one per line.
+func SaveLines(path string, lines []string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// LoadFile reads "key = value" lines; blank lines and # comments are skipped.
+// A missing file yields an empty map and no error.
+func LoadFile(path string) (map[string]string, error) {
+	vals := make(map[string]string)
+	if path == "" {
+		return vals, nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return vals, nil
+	}
+	if err != nil {
+		return vals, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		vals[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return vals, sc.Err()
+}
+
+// SaveValue sets key in the config file, rewriting an existing line in place
+// and keeping everything else (including comments) untouched.
+func SaveValue(path, key, value string) error {
+	var lines []string
+	if b, err := os.ReadFile(path); err == nil {
+		if trimmed := strings.TrimRight(string(b), "\n"); trimmed != "" {
+			lines = strings.Split(trimmed, "\n")
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	entry := key + " = " + value
+	replaced := false
+	for i, line := range lines {
+		k, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && !strings.HasPrefix(strings.TrimSpace(line), "#") && strings.TrimSpace(k) == key {
+			lines[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		lines = append(lines, entry)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
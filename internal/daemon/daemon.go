@@ -0,0 +1,181 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/retention"
+)
+
+// Server answers line-based requests on a Unix socket:
+//
+//	get        -> latest sample as one JSON line
+//	subscribe  -> NDJSON stream of every new sample until the client hangs up
+//	history [d] -> JSON array of the retained samples, optionally only the last d (e.g. "history 1m")
+type Server struct {
+	path string
+	ring *retention.Ring
+
+	mu     sync.RWMutex
+	latest *model.Sample
+	subs   map[chan model.Sample]struct{}
+}
+
+func New(path string, ring *retention.Ring) *Server {
+	return &Server{path: path, ring: ring, subs: make(map[chan model.Sample]struct{})}
+}
+
+// Run listens on the socket and fans samples out to clients until ctx is
+// done or samples closes. The socket file is removed on return.
+func (s *Server) Run(ctx context.Context, samples <-chan model.Sample) error {
+	// A stale socket from a crashed run would make Listen fail; one that
+	// still answers belongs to a running daemon and is left alone
+	if fi, err := os.Lstat(s.path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		conn, err := net.Dial("unix", s.path)
+		switch {
+		case err == nil:
+			conn.Close()
+			return fmt.Errorf("%s: already serving", s.path)
+		case errors.Is(err, syscall.ECONNREFUSED):
+			_ = os.Remove(s.path)
+		}
+	}
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(s.path)
+	defer ln.Close()
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	go s.broadcast(ctx, samples, ln)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handle(ctx, conn)
+	}
+}
+
+func (s *Server) broadcast(ctx context.Context, samples <-chan model.Sample, ln net.Listener) {
+	defer ln.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case samp, ok := <-samples:
+			if !ok {
+				return
+			}
+			s.ring.Add(samp)
+			s.mu.Lock()
+			s.latest = &samp
+			for ch := range s.subs {
+				select {
+				case ch <- samp:
+				default: // slow subscriber: drop rather than stall everyone
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	enc := json.NewEncoder(conn)
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(sc.Text()), " ")
+		switch cmd {
+		case "get":
+			s.mu.RLock()
+			latest := s.latest
+			s.mu.RUnlock()
+			if latest == nil {
+				fmt.Fprintln(conn, `{"error":"no sample yet"}`)
+				continue
+			}
+			if err := enc.Encode(latest); err != nil {
+				return
+			}
+		case "subscribe":
+			s.subscribe(ctx, conn, enc)
+			return
+		case "history":
+			var d time.Duration
+			if arg = strings.TrimSpace(arg); arg != "" {
+				var err error
+				if d, err = time.ParseDuration(arg); err != nil {
+					fmt.Fprintln(conn, `{"error":"bad duration (e.g. history 1m)"}`)
+					continue
+				}
+			}
+			samples := s.ring.Since(d)
+			if samples == nil {
+				samples = []model.Sample{}
+			}
+			if err := enc.Encode(samples); err != nil {
+				return
+			}
+		case "":
+		default:
+			fmt.Fprintln(conn, `{"error":"unknown command (use get, subscribe or history)"}`)
+		}
+	}
+}
+
+func (s *Server) subscribe(ctx context.Context, conn net.Conn, enc *json.Encoder) {
+	ch := make(chan model.Sample, 4)
+	s.mu.Lock()
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, ch)
+		s.mu.Unlock()
+	}()
+
+	// Notice hang-ups even while no sample is due
+	gone := make(chan struct{})
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				close(gone)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-gone:
+			return
+		case samp := <-ch:
+			if err := enc.Encode(samp); err != nil {
+				return
+			}
+		}
+	}
+}
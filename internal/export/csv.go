@@ -0,0 +1,136 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// Row is one CSV record of the headline metrics for a single sample.
+type Row struct {
+	Time      time.Time
+	CPU       float64 // percent
+	Mem       float64 // percent
+	NetRx     float64 // Mb/s
+	NetTx     float64 // Mb/s
+	DiskRead  float64 // MB/s
+	DiskWrite float64 // MB/s
+	PerCore   []float64
+}
+
+// RowFromSample extracts the exported columns from a sample.
+func RowFromSample(s model.Sample) Row {
+	memPct := 0.0
+	if s.Memory.TotalBytes > 0 {
+		memPct = float64(s.Memory.UsedBytes) * 100 / float64(s.Memory.TotalBytes)
+	}
+	return Row{
+		Time:      s.Timestamp,
+		CPU:       s.CPU.Total,
+		Mem:       memPct,
+		NetRx:     s.IO.NetRxMbps,
+		NetTx:     s.IO.NetTxMbps,
+		DiskRead:  s.IO.DiskReadMBs,
+		DiskWrite: s.IO.DiskWriteMBs,
+		PerCore:   s.CPU.PerCore,
+	}
+}
+
+// Header returns the CSV column names for the given number of cores.
+func Header(cores int) []string {
+	h := []string{"timestamp", "cpu_pct", "mem_pct", "net_rx_mbps", "net_tx_mbps", "disk_read_mbs", "disk_write_mbs"}
+	for i := 0; i < cores; i++ {
+		h = append(h, fmt.Sprintf("core%d_pct", i))
+	}
+	return h
+}
+
+func (r Row) record(cores int) []string {
+	rec := []string{
+		r.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+		ff(r.CPU), ff(r.Mem), ff(r.NetRx), ff(r.NetTx), ff(r.DiskRead), ff(r.DiskWrite),
+	}
+	for i := 0; i < cores; i++ {
+		if i < len(r.PerCore) {
+			rec = append(rec, ff(r.PerCore[i]))
+		} else {
+			rec = append(rec, "")
+		}
+	}
+	return rec
+}
+
+// WriteCSV writes a header row followed by one record per row.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cores := 0
+	for _, r := range rows {
+		if len(r.PerCore) > cores {
+			cores = len(r.PerCore)
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Header(cores)); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(r.record(cores)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CSVAppender appends one row per sample to a file, writing the header
+// only when the file starts out empty.
+type CSVAppender struct {
+	f     *os.File
+	w     *csv.Writer
+	cores int
+	wrote bool
+}
+
+// NewCSVAppender opens (or creates) path for appending.
+func NewCSVAppender(path string) (*CSVAppender, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	a := &CSVAppender{f: f, w: csv.NewWriter(f), cores: -1}
+	if fi, err := f.Stat(); err == nil && fi.Size() > 0 {
+		a.wrote = true
+	}
+	return a, nil
+}
+
+// Append writes s as a CSV record and flushes it to disk.
+func (a *CSVAppender) Append(s model.Sample) error {
+	r := RowFromSample(s)
+	if a.cores < 0 {
+		a.cores = len(r.PerCore)
+	}
+	if !a.wrote {
+		if err := a.w.Write(Header(a.cores)); err != nil {
+			return err
+		}
+		a.wrote = true
+	}
+	if err := a.w.Write(r.record(a.cores)); err != nil {
+		return err
+	}
+	a.w.Flush()
+	return a.w.Error()
+}
+
+// Close flushes and closes the underlying file.
+func (a *CSVAppender) Close() error {
+	a.w.Flush()
+	return a.f.Close()
+}
+
+func ff(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
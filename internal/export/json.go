@@ -0,0 +1,72 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// JSONFields lists the top-level Sample sections that can be selected,
+// named by their JSON keys.
+func JSONFields() []string {
+	t := reflect.TypeOf(model.Sample{})
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// Projector trims samples to selected top-level sections before encoding.
+// schema_version and timestamp are always kept so consumers can version and
+// order records.
+type Projector struct {
+	keep map[string]bool // nil keeps everything
+}
+
+// NewProjector validates fields against JSONFields; no fields means no projection.
+func NewProjector(fields []string) (*Projector, error) {
+	if len(fields) == 0 {
+		return &Projector{}, nil
+	}
+	valid := make(map[string]bool)
+	for _, f := range JSONFields() {
+		valid[f] = true
+	}
+	keep := map[string]bool{"schema_version": true, "timestamp": true}
+	for _, f := range fields {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !valid[f] {
+			return nil, fmt.Errorf("unknown JSON field %q (valid: %s)", f, strings.Join(JSONFields(), ","))
+		}
+		keep[f] = true
+	}
+	return &Projector{keep: keep}, nil
+}
+
+// Project returns the value to marshal in place of s.
+func (p *Projector) Project(s model.Sample) (any, error) {
+	if p == nil || p.keep == nil {
+		return s, nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(b, &sections); err != nil {
+		return nil, err
+	}
+	for k := range sections {
+		if !p.keep[k] {
+			delete(sections, k)
+		}
+	}
+	return sections, nil
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// rotateFlushEvery bounds how long buffered output waits before reaching the
+// file, so a tail -f of a long capture stays close to live.
+const rotateFlushEvery = time.Second
+
+// RotatingFile appends to path through a buffer that is flushed at most
+// rotateFlushEvery apart. Once a write would grow the file past maxSize it
+// is renamed to path.1 (path.1.gz when compressing, done in the background),
+// older files shift up, and only keep of them are kept. maxSize <= 0
+// disables rotation. Writes are never split across files, so each Write
+// should be one whole record.
+type RotatingFile struct {
+	path     string
+	maxSize  int64
+	keep     int
+	compress bool
+
+	f       *os.File
+	w       *bufio.Writer
+	size    int64
+	flushed time.Time
+	gzipWG  sync.WaitGroup
+}
+
+// NewRotatingFile opens (or creates) path for appending.
+func NewRotatingFile(path string, maxSize int64, keep int, compress bool) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, keep: keep, compress: compress}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	r.f, r.w, r.size, r.flushed = f, bufio.NewWriter(f), 0, time.Now()
+	if fi, err := f.Stat(); err == nil {
+		r.size = fi.Size()
+	}
+	return nil
+}
+
+// Write buffers p, rotating first when it would overflow maxSize.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.w.Write(p)
+	r.size += int64(n)
+	if err == nil && time.Since(r.flushed) >= rotateFlushEvery {
+		err = r.Flush()
+	}
+	return n, err
+}
+
+// Flush hands buffered output to the kernel.
+func (r *RotatingFile) Flush() error {
+	r.flushed = time.Now()
+	return r.w.Flush()
+}
+
+// Close flushes, syncs and closes the file, waiting for any compression
+// still running.
+func (r *RotatingFile) Close() error {
+	err := r.w.Flush()
+	if serr := r.f.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	r.gzipWG.Wait()
+	return err
+}
+
+// rotated is the name of the n-th rotated file.
+func (r *RotatingFile) rotated(n int) string {
+	name := fmt.Sprintf("%s.%d", r.path, n)
+	if r.compress {
+		name += ".gz"
+	}
+	return name
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.w.Flush(); err != nil {
+		return err
+	}
+	_ = r.f.Sync()
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	r.gzipWG.Wait() // the previous path.1 must be final before it moves
+	if r.keep <= 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+	_ = os.Remove(r.rotated(r.keep))
+	for n := r.keep - 1; n >= 1; n-- {
+		if err := os.Rename(r.rotated(n), r.rotated(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if !r.compress {
+		if err := os.Rename(r.path, r.rotated(1)); err != nil {
+			return err
+		}
+		return r.open()
+	}
+	// Compress from a private name so the live file can be reopened at once
+	pending := fmt.Sprintf("%s.1.tmp", r.path)
+	if err := os.Rename(r.path, pending); err != nil {
+		return err
+	}
+	r.gzipWG.Add(1)
+	go func() {
+		defer r.gzipWG.Done()
+		if err := gzipFile(pending, r.rotated(1)); err == nil {
+			_ = os.Remove(pending)
+		}
+	}()
+	return r.open()
+}
+
+// gzipFile writes a gzip copy of src to dst.
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -1,105 +1,335 @@
 package model
 
-import "time"
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// SchemaVersion identifies the JSON shape of Sample. Bump it whenever a
+// field is added, removed, renamed or changes meaning.
+const SchemaVersion = 30
 
 // CPU aggregates instantaneous CPU usage.
 type CPU struct {
-	Total   float64   // percent 0-100
-	PerCore []float64 // per-core percent
-	Load1   float64
-	Load5   float64
-	Load15  float64
+	Total   float64   `json:"total_pct"`    // percent 0-100
+	PerCore []float64 `json:"per_core_pct"` // per-core percent
+	IOWait  float64   `json:"iowait_pct"`   // share of CPU time idle waiting on IO; not part of Total
+	Steal   float64   `json:"steal_pct"`    // time the hypervisor ran someone else while this VM wanted CPU
+	Guest   float64   `json:"guest_pct"`    // time spent running guest VMs (guest + guest_nice)
+	Load1   float64   `json:"load1"`
+	Load5   float64   `json:"load5"`
+	Load15  float64   `json:"load15"`
+}
+
+// Tasks counts processes and threads system-wide, like top's "Tasks:" line.
+// Running and Blocked come from the kernel's run queue (threads) where the
+// platform reports it, else from the states of the sampled processes.
+type Tasks struct {
+	Processes int `json:"processes"`
+	Threads   int `json:"threads"`
+	Running   int `json:"running"` // runnable now; compare with load1
+	Blocked   int `json:"blocked"` // in uninterruptible (D) sleep
+	Stuck     int `json:"stuck"`   // processes (not threads) in D sleep, from their states
+}
+
+// System holds kernel-wide event rates from /proc/stat, per second; all zero
+// on the first sample and where the platform doesn't report them.
+type System struct {
+	CtxSwitches float64 `json:"ctxt_per_sec"`
+	Interrupts  float64 `json:"intr_per_sec"`
+	Forks       float64 `json:"forks_per_sec"` // processes and threads created
 }
 
 // Memory captures RAM and swap usage in bytes for precision.
 type Memory struct {
-	UsedBytes  uint64
-	TotalBytes uint64
-	SwapUsed   uint64
-	SwapTotal  uint64
-	Cached     uint64
-	Buffers    uint64
+	UsedBytes  uint64 `json:"used_bytes"`
+	TotalBytes uint64 `json:"total_bytes"`
+	SwapUsed   uint64 `json:"swap_used_bytes"`
+	SwapTotal  uint64 `json:"swap_total_bytes"`
+	Cached     uint64 `json:"cached_bytes"`
+	Buffers    uint64 `json:"buffers_bytes"`
+
+	// From /proc/meminfo
+	Slab           uint64 `json:"slab_bytes"`
+	Dirty          uint64 `json:"dirty_bytes"`
+	Writeback      uint64 `json:"writeback_bytes"`
+	HugePagesTotal uint64 `json:"hugepages_total"` // pages, not bytes
+	HugePagesFree  uint64 `json:"hugepages_free"`
+	HugePageSize   uint64 `json:"hugepage_size_bytes"`
+
+	// Swap traffic from /proc/vmstat (pswpin/pswpout): unlike SwapUsed it
+	// shows whether the system is thrashing right now
+	SwapInBps  float64 `json:"swap_in_bytes_per_sec"`
+	SwapOutBps float64 `json:"swap_out_bytes_per_sec"`
+}
+
+// Zram sums /sys/block/zram*/mm_stat across devices; Devices == 0 means no zram.
+type Zram struct {
+	Devices    int    `json:"devices"`
+	OrigBytes  uint64 `json:"orig_bytes"`     // uncompressed data stored
+	ComprBytes uint64 `json:"compr_bytes"`    // compressed size of that data
+	MemUsed    uint64 `json:"mem_used_bytes"` // RAM consumed including allocator overhead
+}
+
+// Ratio returns the compression ratio, or 0 when nothing is stored.
+func (z Zram) Ratio() float64 {
+	if z.ComprBytes == 0 {
+		return 0
+	}
+	return float64(z.OrigBytes) / float64(z.ComprBytes)
 }
 
 // IO holds disk and network throughput numbers.
 type IO struct {
-	DiskReadMBs  float64
-	DiskWriteMBs float64
-	NetRxMbps    float64
-	NetTxMbps    float64
-	PerDevice    []IODevice
+	DiskReadMBs  float64    `json:"disk_read_mbs"`
+	DiskWriteMBs float64    `json:"disk_write_mbs"`
+	NetRxMbps    float64    `json:"net_rx_mbps"`
+	NetTxMbps    float64    `json:"net_tx_mbps"`
+	PerDevice    []IODevice `json:"per_device"`
+	PerNIC       []NetIface `json:"per_nic"`
+}
+
+// NetIface captures per-interface network throughput.
+type NetIface struct {
+	Name   string  `json:"name"`
+	RxMbps float64 `json:"rx_mbps"`
+	TxMbps float64 `json:"tx_mbps"`
+}
+
+// NetStates tallies sockets by TCP state plus the UDP socket count, over
+// IPv4 and IPv6.
+type NetStates struct {
+	Established int `json:"established"`
+	SynSent     int `json:"syn_sent"`
+	SynRecv     int `json:"syn_recv"`
+	FinWait1    int `json:"fin_wait1"`
+	FinWait2    int `json:"fin_wait2"`
+	TimeWait    int `json:"time_wait"`
+	Close       int `json:"close"`
+	CloseWait   int `json:"close_wait"`
+	LastAck     int `json:"last_ack"`
+	Listen      int `json:"listen"`
+	Closing     int `json:"closing"`
+	UDP         int `json:"udp"`
 }
 
 // IODevice captures per-block-device throughput.
 type IODevice struct {
-	Name     string
-	ReadMBs  float64
-	WriteMBs float64
+	Name     string  `json:"name"`
+	ReadMBs  float64 `json:"read_mbs"`
+	WriteMBs float64 `json:"write_mbs"`
+	TempC    float64 `json:"temp_c,omitempty"` // drive sensor (nvme/drivetemp hwmon); 0 when absent
 }
 
 // GPU holds a single device snapshot.
 type GPU struct {
-	Name       string
-	Util       float64 // percent
-	MemUsedMB  float64
-	MemTotalMB float64
-	TempC      float64
+	Name       string  `json:"name"`
+	Util       float64 `json:"util_pct"` // percent
+	MemUsedMB  float64 `json:"mem_used_mb"`
+	MemTotalMB float64 `json:"mem_total_mb"`
+	TempC      float64 `json:"temp_c"`
+	VideoUtil  float64 `json:"video_util_pct,omitempty"` // media engines busy (intel_gpu_top); Util is render/3D
+	FreqMHz    float64 `json:"freq_mhz,omitempty"`       // actual GPU (SM) clock
+
+	// nvidia-smi only; 0 where the card reports [N/A]
+	PowerW      float64 `json:"power_w,omitempty"`
+	PowerLimitW float64 `json:"power_limit_w,omitempty"` // enforced cap; draw at the cap means power-throttled
+	MemClockMHz float64 `json:"mem_clock_mhz,omitempty"`
+}
+
+// GPUProcess is a compute app holding GPU memory.
+type GPUProcess struct {
+	PID       int     `json:"pid"`
+	Name      string  `json:"name"`
+	MemUsedMB float64 `json:"mem_used_mb"`
 }
 
 // Battery shows power state; absent if Percent == 0 and State is empty.
 type Battery struct {
-	Percent          float64
-	State            string
-	SecondsRemaining int64
+	Percent          float64 `json:"percent"`
+	State            string  `json:"state"`
+	SecondsRemaining int64   `json:"seconds_remaining"` // to empty when discharging, to full when charging; 0 if unknown
+	PowerW           float64 `json:"power_w"`           // summed draw across batteries
 }
 
 // Process is a lightweight top entry.
 type Process struct {
-	PID      int
-	Nice     int
-	CPU      float64
-	Memory   float64
-	Command  string
-	FDCount  int
-	ReadKBs  float64
-	WriteKBs float64
-	FDDiff   int
-}
-
-// Cgroup summarizes CPU usage by unit/name.
+	PID      int     `json:"pid"`
+	PPID     int     `json:"ppid"`
+	Nice     int     `json:"nice"`         // -20..19; raw getpriority(2) 20-nice on Linux before schema 30
+	CPU      float64 `json:"cpu_pct"`      // per-core sum, may exceed 100
+	CPUNorm  float64 `json:"cpu_norm_pct"` // CPU divided by core count, 0-100 of the whole machine
+	Memory   float64 `json:"mem_pct"`
+	Command  string  `json:"command"` // full cmdline, or comm when it is empty (kernel threads, zombies)
+	FDCount  int     `json:"fd_count"`
+	ReadKBs  float64 `json:"read_kbs"`
+	WriteKBs float64 `json:"write_kbs"`
+	FDDiff   int     `json:"fd_diff"`
+	MemDiff  int64   `json:"mem_diff_bytes"` // RSS change since the previous sample
+	Conns    int     `json:"conns"`          // open sockets; only sampled for the top CPU consumers
+
+	OOMScore    int `json:"oom_score"`     // /proc/<pid>/oom_score, 0-1000 (higher dies first)
+	OOMScoreAdj int `json:"oom_score_adj"` // /proc/<pid>/oom_score_adj, -1000..1000
+
+	Threads   int    `json:"threads"`
+	User      string `json:"user"`
+	Container string `json:"container,omitempty"` // container name (or short ID) from the cgroup path
+	State     string `json:"state"`               // ps letter: R running, S sleeping, D uninterruptible, Z zombie, T stopped, I idle
+
+	ReadTotal  uint64 `json:"read_total_bytes"`  // cumulative since process start
+	WriteTotal uint64 `json:"write_total_bytes"` // cumulative since process start
+
+	CPUTimeSeconds float64 `json:"cpu_time_seconds"` // utime+stime since process start
+
+	MinorFaults float64 `json:"minor_faults_per_s"`
+	MajorFaults float64 `json:"major_faults_per_s"` // faults that hit disk; sustained rates mean thrashing
+
+	// IOWaitMs is milliseconds per second spent blocked on block IO (Linux
+	// delay accounting; stays 0 unless kernel.task_delayacct is on). High
+	// delay at modest throughput marks a victim of contention, not its cause.
+	IOWaitMs float64 `json:"io_wait_ms_per_s"`
+
+	// Set when /proc/<pid>/io or /proc/<pid>/fd could not be read for lack of
+	// permission (another user's process without root); the values are 0.
+	IODenied bool `json:"io_denied,omitempty"`
+	FDDenied bool `json:"fd_denied,omitempty"`
+
+	// Raw name pieces so the UI can switch display modes without resampling
+	Comm string `json:"comm"`
+	Exe  string `json:"exe"` // empty when unreadable (other users, non-root)
+}
+
+// MatchesName reports whether the glob pattern (path.Match) matches the
+// kernel comm name, the executable's basename or the basename of the first
+// command word; malformed patterns never match.
+func (p Process) MatchesName(pattern string) bool {
+	first, _, _ := strings.Cut(strings.TrimSpace(p.Command), " ")
+	for _, n := range []string{p.Comm, path.Base(p.Exe), path.Base(first)} {
+		if n == "" || n == "." || n == "/" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, n); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcInfo is fetched on demand for the detail view; it is not part of Sample.
+// Env values whose names look like secrets are redacted.
+type ProcInfo struct {
+	PID     int      `json:"pid"`
+	Exe     string   `json:"exe"`
+	Cmdline string   `json:"cmdline"`
+	Env     []string `json:"env"`
+}
+
+// Cgroup summarizes usage by unit/name. Memory and IO come from the cgroup
+// itself (v2 memory.current/io.stat, v1 memory only) and are 0 when unavailable.
 type Cgroup struct {
-	Name string
-	CPU  float64
+	Name         string  `json:"name"`
+	CPU          float64 `json:"cpu_pct"`
+	MemBytes     uint64  `json:"mem_bytes"`
+	IOReadBytes  uint64  `json:"io_read_bytes"`  // cumulative
+	IOWriteBytes uint64  `json:"io_write_bytes"` // cumulative
+}
+
+// Unit aggregates processes by the systemd .service/.scope found in their
+// cgroup path, similar to systemd-cgtop. MemBytes is the summed RSS.
+type Unit struct {
+	Name     string  `json:"name"`
+	CPU      float64 `json:"cpu_pct"`
+	MemBytes uint64  `json:"mem_bytes"`
+	Procs    int     `json:"procs"`
+}
+
+// Container aggregates processes by the docker/podman/CRI container ID in
+// their cgroup path. Name is the container's name when it could be resolved,
+// else the short ID; MemBytes is the summed RSS.
+type Container struct {
+	Name     string  `json:"name"`
+	ID       string  `json:"id"`
+	CPU      float64 `json:"cpu_pct"`
+	MemBytes uint64  `json:"mem_bytes"`
+	Procs    int     `json:"procs"`
+}
+
+// Limits are system-wide kernel resources that stall or break services when
+// they run out: the random pool's entropy estimate (entropy_avail out of
+// poolsize bits) and the file handle table (/proc/sys/fs/file-nr). Zero
+// where unavailable.
+type Limits struct {
+	EntropyAvail   uint64 `json:"entropy_avail"`
+	EntropyPool    uint64 `json:"entropy_pool"`
+	FilesAllocated uint64 `json:"files_allocated"`
+	FilesMax       uint64 `json:"files_max"`
+}
+
+// FilesPct is the share of the file handle table in use, 0-100.
+func (l Limits) FilesPct() float64 {
+	if l.FilesMax == 0 {
+		return 0
+	}
+	return float64(l.FilesAllocated) / float64(l.FilesMax) * 100
 }
 
 // Inotify collects watch stats.
 type Inotify struct {
-	MaxUserWatches   uint64
-	MaxUserInstances uint64
-	NrWatches        uint64
+	MaxUserWatches   uint64        `json:"max_user_watches"`
+	MaxUserInstances uint64        `json:"max_user_instances"`
+	NrWatches        uint64        `json:"nr_watches"`
+	Procs            []InotifyProc `json:"procs"` // top watch holders, most first
+}
+
+// InotifyProc is a process holding inotify watches.
+type InotifyProc struct {
+	PID     int    `json:"pid"`
+	Command string `json:"command"`
+	Watches int    `json:"watches"`
+}
+
+// Access counts per-process reads refused for lack of permission, which
+// happens for other users' processes when not running as root. Checked is
+// the number of processes whose FD and IO counters were read this sample.
+type Access struct {
+	Checked  int `json:"checked"`
+	IODenied int `json:"io_denied"`
+	FDDenied int `json:"fd_denied"`
 }
 
 // Temp is a thermal sensor reading.
 type Temp struct {
-	Zone string
-	Temp float64
+	Zone string  `json:"zone"`
+	Temp float64 `json:"temp_c"`
 }
 
 // Sample is the full snapshot exchanged between sampler, UI, and JSON exporter.
 type Sample struct {
-	Timestamp time.Time
-	Interval  time.Duration
-	CPU       CPU
-	Memory    Memory
-	IO        IO
-	GPUs      []GPU
-	Battery   Battery
-	Top       []Process
-	Throttled []Process
-	Cgroups   []Cgroup
-	Inotify   Inotify
-	Temps     []Temp
+	SchemaVersion int           `json:"schema_version"`
+	Timestamp     time.Time     `json:"timestamp"`
+	Interval      time.Duration `json:"interval_ns"`
+	CPU           CPU           `json:"cpu"`
+	Tasks         Tasks         `json:"tasks"`
+	System        System        `json:"system"`
+	Memory        Memory        `json:"memory"`
+	Zram          Zram          `json:"zram"`
+	IO            IO            `json:"io"`
+	NetStates     *NetStates    `json:"net_states,omitempty"` // nil unless enabled
+	GPUs          []GPU         `json:"gpus"`
+	GPUProcs      []GPUProcess  `json:"gpu_procs"`
+	Battery       Battery       `json:"battery"`
+	Top           []Process     `json:"top"`
+	Throttled     []Process     `json:"throttled"`
+	Stuck         []Process     `json:"stuck"` // up to 32 processes in D sleep, longest-blocked first
+	Cgroups       []Cgroup      `json:"cgroups"`
+	Units         []Unit        `json:"units"`
+	Containers    []Container   `json:"containers"`
+	Inotify       Inotify       `json:"inotify"`
+	Limits        Limits        `json:"limits"`
+	Access        Access        `json:"access"`
+	Temps         []Temp        `json:"temps"`
 }
 
 // Zero returns an empty sample for initialization.
-func Zero() Sample { return Sample{Timestamp: time.Now()} }
+func Zero() Sample { return Sample{SchemaVersion: SchemaVersion, Timestamp: time.Now()} }
@@ -0,0 +1,133 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// Client streams samples from sysmoni on another host by running it over
+// ssh in -json-stream mode and decoding its NDJSON stdout.
+type Client struct {
+	Target string   // ssh destination, e.g. user@host
+	Cmd    string   // remote sysmoni binary
+	Args   []string // extra flags for the remote sysmoni
+
+	mu  sync.Mutex
+	err error
+}
+
+func New(target, cmd string, args []string) *Client {
+	if cmd == "" {
+		cmd = "sysmoni"
+	}
+	return &Client{Target: target, Cmd: cmd, Args: args}
+}
+
+// Stream starts the remote sampler and returns its samples until ctx is done
+// or the connection drops; Err then explains why the channel closed.
+func (c *Client) Stream(ctx context.Context) <-chan model.Sample {
+	ch := make(chan model.Sample)
+	go func() {
+		defer close(ch)
+		c.setErr(c.run(ctx, ch))
+	}()
+	return ch
+}
+
+// Err is the reason the last stream ended, nil while it is still running.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Client) setErr(err error) {
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+func (c *Client) run(ctx context.Context, ch chan<- model.Sample) error {
+	// BatchMode: a password prompt would fight the TUI for the terminal.
+	// ssh hands everything after the target to the remote shell as one
+	// string, so the flags are quoted to reach sysmoni unexpanded.
+	args := []string{"-T", "-o", "BatchMode=yes", "--", c.Target, c.Cmd, "-json-stream"}
+	for _, a := range c.Args {
+		args = append(args, shellQuote(a))
+	}
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	lastErr := make(chan string, 1)
+	go func() {
+		var last string
+		sc := bufio.NewScanner(stderr)
+		for sc.Scan() {
+			if line := strings.TrimSpace(sc.Text()); line != "" {
+				last = line
+			}
+		}
+		lastErr <- last
+	}()
+
+	dec := json.NewDecoder(stdout)
+	var decodeErr error
+	for {
+		var s model.Sample
+		if err := dec.Decode(&s); err != nil {
+			if !errors.Is(err, io.EOF) {
+				decodeErr = fmt.Errorf("decode remote sample: %w", err)
+			}
+			break
+		}
+		select {
+		case ch <- s:
+		case <-ctx.Done():
+			_ = cmd.Wait()
+			return ctx.Err()
+		}
+	}
+	if decodeErr != nil {
+		// The remote stream never ends on its own, so the drain below
+		// would block forever
+		_ = cmd.Process.Kill()
+	}
+	// Finish reading both pipes before Wait, which closes them
+	_, _ = io.Copy(io.Discard, stdout)
+	msg := <-lastErr
+	waitErr := cmd.Wait()
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case decodeErr != nil:
+		return decodeErr
+	case waitErr != nil && msg != "":
+		return fmt.Errorf("%s: %s", c.Target, msg)
+	case waitErr != nil:
+		return fmt.Errorf("%s: %w", c.Target, waitErr)
+	}
+	return fmt.Errorf("%s: remote stream ended", c.Target)
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,78 @@
+package retention
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// Ring keeps the newest full samples, bounded by count and by age relative to
+// the newest sample. It is safe for concurrent use.
+type Ring struct {
+	mu     sync.RWMutex
+	maxAge time.Duration
+	buf    []model.Sample
+	start  int // index of the oldest sample
+	n      int
+}
+
+// New returns a ring holding at most max samples spanning at most maxAge
+// (0 = no age limit). max < 1 is treated as 1.
+func New(max int, maxAge time.Duration) *Ring {
+	if max < 1 {
+		max = 1
+	}
+	return &Ring{maxAge: maxAge, buf: make([]model.Sample, max)}
+}
+
+// Add appends s, evicting the oldest sample when full and any samples that
+// have aged out.
+func (r *Ring) Add(s model.Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.n == len(r.buf) {
+		r.buf[r.start] = model.Sample{}
+		r.start = (r.start + 1) % len(r.buf)
+		r.n--
+	}
+	r.buf[(r.start+r.n)%len(r.buf)] = s
+	r.n++
+	if r.maxAge > 0 {
+		cutoff := s.Timestamp.Add(-r.maxAge)
+		for r.n > 1 && r.buf[r.start].Timestamp.Before(cutoff) {
+			r.buf[r.start] = model.Sample{}
+			r.start = (r.start + 1) % len(r.buf)
+			r.n--
+		}
+	}
+}
+
+// Len reports how many samples are retained.
+func (r *Ring) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.n
+}
+
+// Since returns a copy of the retained samples taken within d of the newest,
+// oldest first; d <= 0 returns them all.
+func (r *Ring) Since(d time.Duration) []model.Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.n == 0 {
+		return nil
+	}
+	first := 0
+	if d > 0 {
+		cutoff := r.buf[(r.start+r.n-1)%len(r.buf)].Timestamp.Add(-d)
+		for first < r.n-1 && r.buf[(r.start+first)%len(r.buf)].Timestamp.Before(cutoff) {
+			first++
+		}
+	}
+	out := make([]model.Sample, 0, r.n-first)
+	for i := first; i < r.n; i++ {
+		out = append(out, r.buf[(r.start+i)%len(r.buf)])
+	}
+	return out
+}
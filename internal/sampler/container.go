@@ -0,0 +1,95 @@
+package sampler
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// containerSegment matches a cgroup path segment naming a container by its
+// 64-hex ID, as written by docker (docker-<id>.scope, /docker/<id>), podman
+// (libpod-<id>.scope), CRI-O (crio-<id>.scope) and containerd
+// (cri-containerd-<id>.scope).
+var containerSegment = regexp.MustCompile(`^(?:[a-z-]+-)?([0-9a-f]{64})(?:\.scope)?$`)
+
+// containerRefresh spaces out docker/podman ps calls for unknown IDs.
+const containerRefresh = 30 * time.Second
+
+// containerID returns the container ID in a cgroup path, innermost first, or
+// "" outside containers.
+func containerID(path string) string {
+	segs := strings.Split(path, "/")
+	for i := len(segs) - 1; i >= 0; i-- {
+		if m := containerSegment.FindStringSubmatch(segs[i]); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// containerName resolves a container ID to its name. Known names are cached
+// for good; unknown IDs are looked up in docker's metadata and, at most once
+// per containerRefresh, by a background docker/podman ps. Until a name is
+// known, and always without ContainerNames, it is the 12-character short ID.
+func (s *Sampler) containerName(id string) string {
+	if !s.ContainerNames {
+		return id[:12]
+	}
+	s.ctrMu.Lock()
+	defer s.ctrMu.Unlock()
+	if name, ok := s.ctrNames[id]; ok {
+		return name
+	}
+	if name := dockerConfigName(id); name != "" {
+		s.ctrNames[id] = name
+		return name
+	}
+	if !s.ctrLookup && time.Since(s.ctrLookedUp) >= containerRefresh {
+		s.ctrLookup, s.ctrLookedUp = true, time.Now()
+		go s.refreshContainerNames()
+	}
+	return id[:12]
+}
+
+// refreshContainerNames asks docker and podman for their running containers.
+func (s *Sampler) refreshContainerNames() {
+	found := make(map[string]string)
+	for _, tool := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		out, err := runCmd(2*time.Second, tool, "ps", "--no-trunc", "--format", "{{.ID}} {{.Names}}")
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if id, name, ok := strings.Cut(strings.TrimSpace(line), " "); ok && len(id) == 64 {
+				found[id] = strings.TrimSpace(name)
+			}
+		}
+	}
+	s.ctrMu.Lock()
+	for id, name := range found {
+		s.ctrNames[id] = name
+	}
+	s.ctrLookup = false
+	s.ctrMu.Unlock()
+}
+
+// dockerConfigName reads a container's name from docker's on-disk metadata,
+// which only root can usually read.
+func dockerConfigName(id string) string {
+	b, err := os.ReadFile(filepath.Join("/var/lib/docker/containers", id, "config.v2.json"))
+	if err != nil {
+		return ""
+	}
+	var cfg struct{ Name string }
+	if json.Unmarshal(b, &cfg) != nil {
+		return ""
+	}
+	return strings.TrimPrefix(cfg.Name, "/")
+}
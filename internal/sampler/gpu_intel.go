@@ -0,0 +1,92 @@
+package sampler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// intel_gpu_top streams samples until killed, so one run is bounded by
+// intelGPUTimeout and stopped after the first sample of intelGPUPeriod.
+const (
+	intelGPUPeriod  = 250 * time.Millisecond
+	intelGPUTimeout = time.Second
+)
+
+// intelGPUSample is the part of an `intel_gpu_top -J` record sysmoni uses.
+// Engine names look like "Render/3D/0", "Video/1" or "VideoEnhance/0".
+type intelGPUSample struct {
+	Frequency struct {
+		Actual float64 `json:"actual"`
+	} `json:"frequency"`
+	Engines map[string]struct {
+		Busy float64 `json:"busy"`
+	} `json:"engines"`
+}
+
+// queryIntelGPU reads one sample of the default Intel GPU (integrated or
+// Arc) from intel_gpu_top. It needs root or CAP_PERFMON; any failure, like a
+// missing binary, yields nothing.
+func queryIntelGPU() []model.GPU {
+	if _, err := exec.LookPath("intel_gpu_top"); err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), intelGPUTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "intel_gpu_top", "-J", "-o", "-",
+		"-s", strconv.Itoa(int(intelGPUPeriod.Milliseconds())))
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		return nil
+	}
+	defer func() {
+		cancel() // kills the stream once the first record is in
+		_ = cmd.Wait()
+	}()
+
+	var samp intelGPUSample
+	if err := decodeFirstRecord(out, &samp); err != nil || len(samp.Engines) == 0 {
+		return nil
+	}
+	gpu := model.GPU{Name: "Intel GPU", FreqMHz: samp.Frequency.Actual}
+	for name, e := range samp.Engines {
+		class, _, _ := strings.Cut(name, "/")
+		switch class {
+		case "Render":
+			gpu.Util = max(gpu.Util, e.Busy)
+		case "Video", "VideoEnhance":
+			gpu.VideoUtil = max(gpu.VideoUtil, e.Busy)
+		}
+	}
+	return []model.GPU{gpu}
+}
+
+// decodeFirstRecord decodes the first object of intel_gpu_top's output,
+// which newer versions wrap in a "[ {...}, {...}" array that is never closed
+// and older ones print as bare objects.
+func decodeFirstRecord(r io.Reader, v any) error {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if !strings.ContainsRune("[, \t\r\n", rune(b)) {
+			break
+		}
+	}
+	if err := br.UnreadByte(); err != nil {
+		return err
+	}
+	return json.NewDecoder(br).Decode(v)
+}
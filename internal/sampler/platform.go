@@ -0,0 +1,41 @@
+package sampler
+
+import (
+	"errors"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// platform supplies the readings gopsutil has no portable API for. The Linux
+// build reads procfs/sysfs (platform_linux.go); other systems get
+// platform_other.go, whose empty results keep the matching panels blank
+// while CPU, memory, disk, network and process metrics work as usual.
+type platform interface {
+	battery() model.Battery
+	zram() model.Zram
+	netStates() *model.NetStates
+	inotifyLimits() model.Inotify
+	limits() model.Limits
+	inotifyHolders() []model.InotifyProc // expensive; the Sampler caches it
+	temps() []model.Temp
+	diskTemps() map[string]float64
+	oomScore(pid int) (score, adj int)
+	blkioDelay(pid int) (ms float64, ok bool) // cumulative time blocked on block IO
+	kernelThread(pid, ppid int32) bool
+	nice(reported int32) int // gopsutil's Nice() as a -20..19 nice value
+	sockets(pid int) int
+	kernelStat() (kernelStat, bool)
+	swapPages() (in, out uint64, ok bool) // cumulative pages swapped since boot
+	procCgroup(pid int) (cgroupRef, error)
+	cgroupStats(cg *model.Cgroup, path string)
+}
+
+var errNoCgroup = errors.New("no cgroup")
+
+// kernelStat holds the system-wide counters of /proc/stat: the run queue as
+// threads runnable now and in D state, and the cumulative context switch,
+// interrupt and fork counts since boot.
+type kernelStat struct {
+	running, blocked  int
+	ctxt, intr, forks uint64
+}
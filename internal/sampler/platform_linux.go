@@ -0,0 +1,616 @@
+//go:build linux
+
+package sampler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// LinuxPanels reports whether this build reads the procfs/sysfs-only panels
+// (cgroups, units, inotify, socket states, zram).
+const LinuxPanels = true
+
+// linuxPlatform reads procfs and sysfs directly.
+type linuxPlatform struct {
+	cgroupV2 bool
+}
+
+func newPlatform() platform {
+	return linuxPlatform{cgroupV2: fileExists("/sys/fs/cgroup/cgroup.controllers")}
+}
+
+// kernelThread reports kthreadd and its children.
+func (linuxPlatform) kernelThread(pid, ppid int32) bool {
+	return pid == 2 || ppid == 2
+}
+
+// blkioDelay reads delayacct_blkio_ticks, field 42 of /proc/<pid>/stat, in
+// USER_HZ (100/s) ticks. The comm field may contain spaces, so fields are
+// counted from the closing parenthesis.
+func (linuxPlatform) blkioDelay(pid int) (float64, bool) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, false
+	}
+	i := bytes.LastIndexByte(b, ')')
+	if i < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(b[i+1:]))
+	const blkioField = 42 - 3 // fields after "pid (comm)" start at 3
+	if len(fields) <= blkioField {
+		return 0, false
+	}
+	ticks, err := strconv.ParseUint(fields[blkioField], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return float64(ticks) * 10, true
+}
+
+// nice undoes the raw getpriority(2) encoding gopsutil passes through on
+// Linux, where the kernel returns 20-nice so the result stays positive.
+func (linuxPlatform) nice(reported int32) int {
+	return 20 - int(reported)
+}
+
+func (linuxPlatform) oomScore(pid int) (score, adj int) {
+	score, _ = readIntFile(fmt.Sprintf("/proc/%d/oom_score", pid))
+	adj, _ = readIntFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid))
+	return score, adj
+}
+
+func (linuxPlatform) battery() model.Battery {
+	battPaths, _ := filepath.Glob("/sys/class/power_supply/BAT*/capacity")
+	var out model.Battery
+	var pctSum, energyNow, energyFull, powerW float64
+	var ttl, ttf int64 // summed time_to_empty_now / time_to_full_now
+	n := 0
+	for _, capPath := range battPaths {
+		base := filepath.Dir(capPath)
+		capBytes, err := os.ReadFile(capPath)
+		if err != nil {
+			continue
+		}
+		n++
+		pctSum += parseFloat(string(capBytes))
+		if out.State == "" || out.State == "Unknown" || out.State == "Full" {
+			stateBytes, _ := os.ReadFile(filepath.Join(base, "status"))
+			out.State = strings.TrimSpace(string(stateBytes))
+		}
+
+		// sysfs reports µW/µWh or µA/µAh+µV depending on the driver; normalise to W/Wh
+		readMicro := func(name string) (float64, bool) {
+			b, err := os.ReadFile(filepath.Join(base, name))
+			if err != nil {
+				return 0, false
+			}
+			return parseFloat(string(b)) / 1e6, true
+		}
+		volts, haveVolts := readMicro("voltage_now")
+		pw, ok := readMicro("power_now")
+		if !ok && haveVolts {
+			if amps, ok := readMicro("current_now"); ok {
+				pw = amps * volts
+			}
+		}
+		powerW += pw
+		if e, ok := readMicro("energy_now"); ok {
+			energyNow += e
+			f, _ := readMicro("energy_full")
+			energyFull += f
+		} else if haveVolts {
+			if c, ok := readMicro("charge_now"); ok {
+				energyNow += c * volts
+				f, _ := readMicro("charge_full")
+				energyFull += f * volts
+			}
+		}
+		readSeconds := func(name string) int64 {
+			b, err := os.ReadFile(filepath.Join(base, name))
+			if err != nil {
+				return 0
+			}
+			v, _ := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+			return v
+		}
+		ttl += readSeconds("time_to_empty_now")
+		ttf += readSeconds("time_to_full_now")
+	}
+	if n == 0 {
+		return model.Battery{}
+	}
+	out.Percent = pctSum / float64(n)
+	if energyFull > 0 {
+		out.Percent = energyNow * 100 / energyFull
+	}
+	out.PowerW = powerW
+	switch {
+	case ttl > 0 && out.State == "Discharging":
+		out.SecondsRemaining = ttl
+	case ttf > 0 && out.State == "Charging":
+		out.SecondsRemaining = ttf
+	case powerW > 0 && out.State == "Discharging":
+		out.SecondsRemaining = int64(energyNow / powerW * 3600)
+	case powerW > 0 && out.State == "Charging" && energyFull > energyNow:
+		out.SecondsRemaining = int64((energyFull - energyNow) / powerW * 3600)
+	}
+	return out
+}
+
+func (linuxPlatform) zram() model.Zram {
+	var z model.Zram
+	paths, _ := filepath.Glob("/sys/block/zram*/mm_stat")
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		// orig_data_size compr_data_size mem_used_total ...
+		fields := strings.Fields(string(b))
+		if len(fields) < 3 {
+			continue
+		}
+		orig, _ := strconv.ParseUint(fields[0], 10, 64)
+		compr, _ := strconv.ParseUint(fields[1], 10, 64)
+		used, _ := strconv.ParseUint(fields[2], 10, 64)
+		z.Devices++
+		z.OrigBytes += orig
+		z.ComprBytes += compr
+		z.MemUsed += used
+	}
+	return z
+}
+
+// netStates tallies /proc/net/tcp{,6} rows by their hex state column and
+// counts /proc/net/udp{,6} rows.
+func (linuxPlatform) netStates() *model.NetStates {
+	ns := &model.NetStates{}
+	states := map[string]*int{
+		"01": &ns.Established, "02": &ns.SynSent, "03": &ns.SynRecv,
+		"04": &ns.FinWait1, "05": &ns.FinWait2, "06": &ns.TimeWait,
+		"07": &ns.Close, "08": &ns.CloseWait, "09": &ns.LastAck,
+		"0A": &ns.Listen, "0B": &ns.Closing,
+	}
+	eachRow := func(path string, fn func(fields []string)) {
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		sc.Scan() // header
+		for sc.Scan() {
+			fn(strings.Fields(sc.Text()))
+		}
+	}
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		eachRow(path, func(fields []string) {
+			if len(fields) > 3 {
+				if c, ok := states[fields[3]]; ok {
+					*c++
+				}
+			}
+		})
+	}
+	for _, path := range []string{"/proc/net/udp", "/proc/net/udp6"} {
+		eachRow(path, func([]string) { ns.UDP++ })
+	}
+	return ns
+}
+
+// inotifyHolders walks /proc/*/fd for anon_inode:inotify fds and counts the
+// "inotify wd:" lines in their fdinfo. Other users' processes are skipped
+// silently when not running as root.
+func (linuxPlatform) inotifyHolders() []model.InotifyProc {
+	pids, err := filepath.Glob("/proc/[0-9]*")
+	if err != nil {
+		return nil
+	}
+	var out []model.InotifyProc
+	for _, dir := range pids {
+		pid, err := strconv.Atoi(filepath.Base(dir))
+		if err != nil {
+			continue
+		}
+		entries, err := os.ReadDir(dir + "/fd")
+		if err != nil {
+			continue
+		}
+		watches := 0
+		for _, e := range entries {
+			link, err := os.Readlink(dir + "/fd/" + e.Name())
+			if err != nil || link != "anon_inode:inotify" {
+				continue
+			}
+			b, err := os.ReadFile(dir + "/fdinfo/" + e.Name())
+			if err != nil {
+				continue
+			}
+			watches += strings.Count(string(b), "inotify wd:")
+		}
+		if watches == 0 {
+			continue
+		}
+		comm, _ := os.ReadFile(dir + "/comm")
+		out = append(out, model.InotifyProc{PID: pid, Command: strings.TrimSpace(string(comm)), Watches: watches})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Watches > out[j].Watches })
+	if len(out) > inotifyTopN {
+		out = out[:inotifyTopN]
+	}
+	return out
+}
+
+func (linuxPlatform) temps() []model.Temp {
+	// hwmon first: coretemp/k10temp expose labelled package and per-core sensors.
+	temps, drivers := hwmonTemps()
+
+	paths, _ := filepath.Glob("/sys/class/thermal/thermal_zone*/temp")
+	sortNatural(paths)
+	for _, p := range paths {
+		dir := filepath.Dir(p)
+		// Some zones are another view of a hwmon sensor already read (e.g.
+		// x86_pkg_temp is coretemp's "Package id 0")
+		if typ, err := os.ReadFile(dir + "/type"); err == nil && drivers[mirroredZones[strings.TrimSpace(string(typ))]] {
+			continue
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		temps = append(temps, model.Temp{Zone: filepath.Base(dir), Temp: parseFloat(string(b)) / 1000})
+	}
+	return temps
+}
+
+// mirroredZones maps thermal zone types to the hwmon driver that reports the
+// same sensor.
+var mirroredZones = map[string]string{
+	"x86_pkg_temp": "coretemp",
+}
+
+// cpuHwmonDrivers lists hwmon chip names that report CPU package/core temps.
+var cpuHwmonDrivers = map[string]bool{
+	"coretemp": true,
+	"k10temp":  true,
+	"zenpower": true,
+}
+
+// hwmonTemps reads /sys/class/hwmon/*/temp*_input for CPU sensor chips,
+// naming each reading after its temp*_label when present. It also returns
+// the drivers that reported; a sensor is read once however many hwmon
+// entries lead to its chip.
+func hwmonTemps() ([]model.Temp, map[string]bool) {
+	var temps []model.Temp
+	drivers := make(map[string]bool)
+	seen := make(map[string]bool)
+	chips, _ := filepath.Glob("/sys/class/hwmon/hwmon*")
+	sortNatural(chips)
+	for _, chip := range chips {
+		nameBytes, err := os.ReadFile(filepath.Join(chip, "name"))
+		if err != nil {
+			continue
+		}
+		driver := strings.TrimSpace(string(nameBytes))
+		if !cpuHwmonDrivers[driver] {
+			continue
+		}
+		resolved, err := filepath.EvalSymlinks(chip)
+		if err != nil {
+			resolved = chip
+		}
+		inputs, _ := filepath.Glob(filepath.Join(chip, "temp*_input"))
+		sortNatural(inputs)
+		for _, in := range inputs {
+			prefix := strings.TrimSuffix(filepath.Base(in), "_input")
+			id := resolved + "/" + prefix
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			b, err := os.ReadFile(in)
+			if err != nil {
+				continue
+			}
+			label := driver + " " + prefix
+			if lb, err := os.ReadFile(filepath.Join(chip, prefix+"_label")); err == nil {
+				if l := strings.TrimSpace(string(lb)); l != "" {
+					label = l
+				}
+			}
+			temps = append(temps, model.Temp{Zone: label, Temp: parseFloat(string(b)) / 1000})
+			drivers[driver] = true
+		}
+	}
+	return temps, drivers
+}
+
+// sortNatural sorts paths with digit runs compared as numbers, so hwmon2
+// comes before hwmon10.
+func sortNatural(paths []string) {
+	sort.Slice(paths, func(i, j int) bool { return naturalLess(paths[i], paths[j]) })
+}
+
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := digitPrefix(a), digitPrefix(b)
+		if da != "" && db != "" {
+			na, _ := strconv.Atoi(da)
+			nb, _ := strconv.Atoi(db)
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
+
+// diskTemps maps whole-disk names (nvme0n1, sda) to the temperature reported
+// by their nvme or drivetemp hwmon chip. NVMe temp1 is the composite sensor.
+func (linuxPlatform) diskTemps() map[string]float64 {
+	temps := make(map[string]float64)
+	chips, _ := filepath.Glob("/sys/class/hwmon/hwmon*")
+	for _, chip := range chips {
+		nameBytes, err := os.ReadFile(filepath.Join(chip, "name"))
+		if err != nil {
+			continue
+		}
+		var disks []string
+		switch strings.TrimSpace(string(nameBytes)) {
+		case "nvme":
+			// device -> the nvme controller, which lists its namespaces
+			disks, _ = filepath.Glob(filepath.Join(chip, "device", "nvme*n*"))
+		case "drivetemp":
+			// device -> the SCSI device, which lists its block device
+			disks, _ = filepath.Glob(filepath.Join(chip, "device", "block", "*"))
+		default:
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(chip, "temp1_input"))
+		if err != nil {
+			continue
+		}
+		for _, d := range disks {
+			temps[filepath.Base(d)] = parseFloat(string(b)) / 1000
+		}
+	}
+	return temps
+}
+
+// sockets counts socket fds under /proc/<pid>/fd.
+func (linuxPlatform) sockets(pid int) int {
+	dir := fmt.Sprintf("/proc/%d/fd", pid)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		link, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if err == nil && strings.HasPrefix(link, "socket:") {
+			n++
+		}
+	}
+	return n
+}
+
+// kernelStat reads /proc/stat in one pass. procs_running and procs_blocked
+// count threads, so they line up with the load average; "intr" is followed
+// by per-IRQ counts, of which only the leading total is kept.
+func (linuxPlatform) kernelStat() (st kernelStat, ok bool) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return st, false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024) // the intr line lists every IRQ
+	for sc.Scan() {
+		key, val, _ := strings.Cut(sc.Text(), " ")
+		val = strings.TrimSpace(val)
+		switch key {
+		case "procs_running":
+			st.running, _ = strconv.Atoi(val)
+			ok = true
+		case "procs_blocked":
+			st.blocked, _ = strconv.Atoi(val)
+		case "ctxt":
+			st.ctxt, _ = strconv.ParseUint(val, 10, 64)
+		case "intr":
+			total, _, _ := strings.Cut(val, " ")
+			st.intr, _ = strconv.ParseUint(total, 10, 64)
+		case "processes":
+			st.forks, _ = strconv.ParseUint(val, 10, 64)
+		}
+	}
+	return st, ok
+}
+
+// swapPages reads the pswpin/pswpout counters from /proc/vmstat.
+func (linuxPlatform) swapPages() (in, out uint64, ok bool) {
+	b, err := os.ReadFile("/proc/vmstat")
+	if err != nil {
+		return 0, 0, false
+	}
+	found := 0
+	for _, line := range strings.Split(string(b), "\n") {
+		key, val, _ := strings.Cut(line, " ")
+		switch key {
+		case "pswpin":
+			in, _ = strconv.ParseUint(val, 10, 64)
+			found++
+		case "pswpout":
+			out, _ = strconv.ParseUint(val, 10, 64)
+			found++
+		}
+	}
+	return in, out, found == 2
+}
+
+func readIntFile(path string) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// procCgroup resolves a process's cgroup from /proc/<pid>/cgroup. On v2
+// the unified "0::" entry is used; on v1 the memory controller's entry is
+// preferred so memory accounting can be read, falling back to the first entry.
+func (pl linuxPlatform) procCgroup(pid int) (cgroupRef, error) {
+	path := fmt.Sprintf("/proc/%d/cgroup", pid)
+	f, err := os.Open(path)
+	if err != nil {
+		return cgroupRef{}, err
+	}
+	defer f.Close()
+	var best string
+	found := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		parts := strings.SplitN(sc.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		controllers, p := parts[1], parts[2]
+		if pl.cgroupV2 {
+			if parts[0] == "0" && controllers == "" {
+				best, found = p, true
+				break
+			}
+			continue
+		}
+		if !found || hasController(controllers, "memory") {
+			best, found = p, true
+			if hasController(controllers, "memory") {
+				break
+			}
+		}
+	}
+	if !found {
+		return cgroupRef{}, errNoCgroup
+	}
+	segs := strings.Split(best, "/")
+	unit := ""
+	for i := len(segs) - 1; i >= 0; i-- {
+		if strings.HasSuffix(segs[i], ".service") || strings.HasSuffix(segs[i], ".scope") {
+			unit = segs[i]
+			break
+		}
+	}
+	for i := len(segs) - 1; i >= 0; i-- {
+		if segs[i] != "" {
+			return cgroupRef{name: segs[i], path: best, unit: unit}, nil
+		}
+	}
+	return cgroupRef{}, errNoCgroup
+}
+
+func hasController(list, name string) bool {
+	for _, c := range strings.Split(list, ",") {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// cgroupStats reads memory and IO accounting for the cgroup at path.
+func (pl linuxPlatform) cgroupStats(cg *model.Cgroup, path string) {
+	if pl.cgroupV2 {
+		base := filepath.Join("/sys/fs/cgroup", path)
+		if b, err := os.ReadFile(filepath.Join(base, "memory.current")); err == nil {
+			cg.MemBytes, _ = strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+		}
+		// io.stat: "<maj:min> rbytes=N wbytes=N rios=N ..." per device
+		if b, err := os.ReadFile(filepath.Join(base, "io.stat")); err == nil {
+			for _, line := range strings.Split(string(b), "\n") {
+				for _, kv := range strings.Fields(line) {
+					k, v, ok := strings.Cut(kv, "=")
+					if !ok {
+						continue
+					}
+					n, _ := strconv.ParseUint(v, 10, 64)
+					switch k {
+					case "rbytes":
+						cg.IOReadBytes += n
+					case "wbytes":
+						cg.IOWriteBytes += n
+					}
+				}
+			}
+		}
+		return
+	}
+	// v1: memory only; blkio accounting is too inconsistent across setups
+	b, err := os.ReadFile(filepath.Join("/sys/fs/cgroup/memory", path, "memory.usage_in_bytes"))
+	if err == nil {
+		cg.MemBytes, _ = strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// inotifyLimits reads the sysctl limits and the system-wide watch count.
+func (linuxPlatform) inotifyLimits() model.Inotify {
+	readUint := func(path string) uint64 {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return 0
+		}
+		v, _ := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+		return v
+	}
+	return model.Inotify{
+		MaxUserWatches:   readUint("/proc/sys/fs/inotify/max_user_watches"),
+		MaxUserInstances: readUint("/proc/sys/fs/inotify/max_user_instances"),
+		NrWatches:        readUint("/proc/sys/fs/inotify/nr_watches"),
+	}
+}
+
+// limits reads the entropy estimate and the file handle table. file-nr is
+// "allocated free max"; free has been 0 since 2.6, so allocated is in use.
+func (linuxPlatform) limits() model.Limits {
+	var l model.Limits
+	if b, err := os.ReadFile("/proc/sys/kernel/random/entropy_avail"); err == nil {
+		l.EntropyAvail, _ = strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	}
+	if b, err := os.ReadFile("/proc/sys/kernel/random/poolsize"); err == nil {
+		l.EntropyPool, _ = strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	}
+	if b, err := os.ReadFile("/proc/sys/fs/file-nr"); err == nil {
+		if f := strings.Fields(string(b)); len(f) == 3 {
+			l.FilesAllocated, _ = strconv.ParseUint(f[0], 10, 64)
+			l.FilesMax, _ = strconv.ParseUint(f[2], 10, 64)
+		}
+	}
+	return l
+}
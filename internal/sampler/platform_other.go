@@ -0,0 +1,47 @@
+//go:build !linux
+
+package sampler
+
+import (
+	"github.com/shirou/gopsutil/v3/host"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// LinuxPanels reports whether this build reads the procfs/sysfs-only panels
+// (cgroups, units, inotify, socket states, zram).
+const LinuxPanels = false
+
+// otherPlatform covers macOS and the BSDs: only temperatures have a portable
+// source (gopsutil's sensors); everything else reports nothing.
+type otherPlatform struct{}
+
+func newPlatform() platform { return otherPlatform{} }
+
+func (otherPlatform) battery() model.Battery              { return model.Battery{} }
+func (otherPlatform) zram() model.Zram                    { return model.Zram{} }
+func (otherPlatform) netStates() *model.NetStates         { return nil }
+func (otherPlatform) inotifyLimits() model.Inotify        { return model.Inotify{} }
+func (otherPlatform) limits() model.Limits                { return model.Limits{} }
+func (otherPlatform) inotifyHolders() []model.InotifyProc { return nil }
+func (otherPlatform) diskTemps() map[string]float64       { return nil }
+func (otherPlatform) oomScore(int) (int, int)             { return 0, 0 }
+func (otherPlatform) blkioDelay(int) (float64, bool)      { return 0, false }
+func (otherPlatform) kernelThread(int32, int32) bool      { return false }
+func (otherPlatform) nice(reported int32) int             { return int(reported) }
+func (otherPlatform) sockets(int) int                     { return 0 }
+func (otherPlatform) kernelStat() (kernelStat, bool)      { return kernelStat{}, false }
+func (otherPlatform) swapPages() (uint64, uint64, bool)   { return 0, 0, false }
+func (otherPlatform) procCgroup(int) (cgroupRef, error)   { return cgroupRef{}, errNoCgroup }
+func (otherPlatform) cgroupStats(*model.Cgroup, string)   {}
+
+func (otherPlatform) temps() []model.Temp {
+	sensors, _ := host.SensorsTemperatures()
+	var temps []model.Temp
+	for _, t := range sensors {
+		if t.Temperature > 0 {
+			temps = append(temps, model.Temp{Zone: t.SensorKey, Temp: t.Temperature})
+		}
+	}
+	return temps
+}
@@ -3,16 +3,23 @@ package sampler
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math/rand"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
+	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
@@ -22,54 +29,251 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 )
 
-// Sampler periodically emits Samples built from procfs and best-effort GPU/Batt reads.
+// Sampler periodically emits Samples built from gopsutil, the platform's
+// extra readers and best-effort GPU reads.
 type Sampler struct {
-	Interval time.Duration
+	Interval   time.Duration
+	intervalCh chan time.Duration
 
-	prevTotal  float64
-	prevIdle   float64
+	// Caps on the CPU-sorted process lists; set before Stream
+	TopN       int
+	ThrottledN int
+
+	// Redact, when set, masks credentials in process command lines; set
+	// before Stream.
+	Redact *Redactor
+
+	// ContainerNames resolves container IDs to names via docker's metadata
+	// and docker/podman ps; off, containers show as short IDs. Set before Stream.
+	ContainerNames bool
+	ctrMu          sync.Mutex
+	ctrNames       map[string]string // full ID -> name
+	ctrLookup      bool              // a ps lookup is running
+	ctrLookedUp    time.Time
+
+	// Light reads FD counts and IO counters only for the processes that make
+	// the Top list rather than for every process; set before Stream.
+	Light bool
+
+	// Glob patterns (filepath.Match) selecting block devices and network
+	// interfaces. Empty include means all; exclude wins. Set before Stream.
+	DiskInclude []string
+	DiskExclude []string
+	NetInclude  []string
+	NetExclude  []string
+
+	// Adaptive stretches Interval while the system is quiet and snaps back to
+	// baseInterval on the first change; set before Stream.
+	Adaptive     bool
+	baseInterval time.Duration
+	stableTicks  int
+	adaptPrev    *model.Sample
+
+	// Jitter moves each sample by a random amount up to ±Jitter (capped at
+	// half the interval) so sampling doesn't lock step with periodic work;
+	// set before Stream. Rates divide by the measured elapsed time instead.
+	Jitter    time.Duration
+	sampledAt time.Time
+	elapsed   time.Duration
+
+	prevCPU    *cpu.TimesStat
 	prevCore   []cpu.TimesStat
 	prevDisk   map[string]disk.IOCountersStat
-	prevNet    []net.IOCountersStat
+	prevNet    *net.IOCountersStat
+	prevNIC    map[string]net.IOCountersStat
 	prevProcIO map[int]procIO
 	prevFD     map[int]int
+	prevRSS    map[int]uint64
+	prevFaults map[int]faults
+	prevBlkio  map[int]float64 // cumulative blkio delay ms
+	prevKstat  *kernelStat
+	prevSwap   *[2]uint64 // pswpin, pswpout
+
+	// Permission failures of this sample's FD/IO reads, reset by topProcs
+	access model.Access
+
+	plat platform
 
 	// Cgroup cache
-	cgroupCache map[int]string
+	cgroupCache map[int]cgroupRef
 	cacheTick   int
 
-	// GPU async
-	gpuData []model.GPU
-	gpuMu   sync.RWMutex
+	// Socket counts are expensive; refreshed for the top CPU procs every few ticks
+	connCache map[int]int
+	connTick  int
+
+	// Per-process inotify watches need a full fd walk; refreshed every few ticks
+	inotifyProcs []model.InotifyProc
+	inotifyTick  int
+
+	// Pinned PIDs are always reported in Top, whatever the caps, and so are
+	// the focused process, the processes matching focusName and all of their
+	// descendants
+	pinMu     sync.Mutex
+	pinned    map[int]bool
+	focusPID  int
+	focusName string
+
+	// Socket state tally is opt-in: busy servers can have huge /proc/net/tcp tables
+	netStatesOn atomic.Bool
+
+	// Kernel threads are left out of the process lists unless asked for
+	kthreadsOn atomic.Bool
+
+	// Battery files are read only while the battery panel wants them
+	battOn atomic.Bool
+
+	// uid -> username, looked up once per uid
+	userCache map[int32]string
+
+	// GPU async: gpuLoop polls every GPUInterval (DefaultGPUInterval when
+	// 0; set before Stream) while gpuOn, and never spawns a GPU tool otherwise
+	GPUInterval time.Duration
+	gpuOn       atomic.Bool
+	gpuData     []model.GPU
+	gpuProcs    []model.GPUProcess
+	gpuMu       sync.RWMutex
+
+	// A panic on a sampling goroutine ends the stream and is kept here
+	errMu sync.Mutex
+	err   error
 }
 
 func New(interval time.Duration) *Sampler {
-	return &Sampler{
-		Interval:    interval,
-		prevDisk:    make(map[string]disk.IOCountersStat),
-		prevProcIO:  make(map[int]procIO),
-		prevFD:      make(map[int]int),
-		cgroupCache: make(map[int]string),
+	if interval <= 0 {
+		interval = time.Second
 	}
+	s := &Sampler{
+		Interval:     interval,
+		baseInterval: interval,
+		intervalCh:   make(chan time.Duration, 1),
+		TopN:         DefaultTopN,
+		ThrottledN:   DefaultThrottledN,
+		prevDisk:     make(map[string]disk.IOCountersStat),
+		prevProcIO:   make(map[int]procIO),
+		prevFD:       make(map[int]int),
+		prevRSS:      make(map[int]uint64),
+		prevFaults:   make(map[int]faults),
+		prevBlkio:    make(map[int]float64),
+		cgroupCache:  make(map[int]cgroupRef),
+		connCache:    make(map[int]int),
+		userCache:    make(map[int32]string),
+		ctrNames:     make(map[string]string),
+		plat:         newPlatform(),
+	}
+	s.gpuOn.Store(true)
+	s.battOn.Store(true)
+	return s
 }
 
+// FromConfig returns a sampler set up from cfg's sampling options, for every
+// frontend that samples locally.
+func FromConfig(cfg config.Config) *Sampler {
+	s := New(cfg.Interval)
+	s.TopN = cfg.TopN
+	s.ThrottledN = cfg.ThrottledN
+	s.Adaptive = cfg.Adaptive
+	s.Light = cfg.Light
+	s.ContainerNames = cfg.ContainerNames
+	s.GPUInterval = cfg.GPUInterval
+	s.Jitter = cfg.Jitter
+	s.SetGPU(cfg.EnableGPU)
+	s.SetBattery(cfg.EnableBatt)
+	if cfg.Redact {
+		s.Redact = NewRedactor(cfg.RedactKeys)
+	}
+	s.DiskInclude, s.DiskExclude = cfg.DiskInclude, cfg.DiskExclude
+	s.NetInclude, s.NetExclude = cfg.NetInclude, cfg.NetExclude
+	s.SetNetStates(cfg.NetStates)
+	s.SetKernelThreads(cfg.KernelThreads)
+	s.SetFocus(cfg.FocusPID, cfg.Track)
+	return s
+}
+
+// cgroupRef identifies a process's cgroup: a display name (last path
+// component) and the hierarchy path used to read accounting files.
+type cgroupRef struct {
+	name string
+	path string
+	unit string // innermost .service/.scope segment, "" outside systemd units
+}
+
+// Default process list caps.
+const (
+	DefaultTopN       = 64
+	DefaultThrottledN = 32
+)
+
+// DefaultGPUInterval is how often GPU tools are polled unless GPUInterval is set.
+const DefaultGPUInterval = 2 * time.Second
+
+const (
+	connSampleTop    = 16 // processes (by CPU) whose sockets are counted
+	connRefreshTicks = 3  // recount sockets every N samples
+	growthExtra      = 16 // fastest-growing procs kept beyond the CPU top list
+	lifetimeExtra    = 8  // biggest lifetime CPU consumers kept beyond it too
+	faultExtra       = 8  // and the heaviest major-fault producers
+
+	adaptiveStableTicks = 5 // quiet samples before the interval doubles
+	adaptiveMaxFactor   = 8 // never stretch beyond 8x the base interval
+
+	inotifyTopN         = 8
+	inotifyRefreshTicks = 5
+)
+
 type procIO struct {
 	read  uint64
 	write uint64
 }
 
-// Stream returns a channel that will receive snapshots until ctx is done.
+type faults struct {
+	minor uint64
+	major uint64
+}
+
+// Stream returns a channel that will receive snapshots until ctx is done or
+// a sampling goroutine panics; Err then reports the panic.
 func (s *Sampler) Stream(ctx context.Context) <-chan model.Sample {
 	ch := make(chan model.Sample)
-	go s.gpuLoop(ctx)
+	ctx, stop := context.WithCancel(ctx)
+	go func() {
+		defer s.recoverPanic(stop)
+		s.gpuLoop(ctx)
+	}()
 	go func() {
-		ticker := time.NewTicker(s.Interval)
-		defer ticker.Stop()
+		// A timer rather than a ticker, so each delay can be jittered. The
+		// schedule advances from the previous deadline, not from when the
+		// sample was consumed, so a slow reader doesn't stretch the interval.
+		next := time.Now().Add(s.nextDelay())
+		timer := time.NewTimer(time.Until(next))
+		defer timer.Stop()
 		defer close(ch)
+		defer s.recoverPanic(stop)
+		reschedule := func(from time.Time) {
+			next = from.Add(s.nextDelay())
+			if now := time.Now(); next.Before(now) {
+				next = now // fell behind: sample at once rather than catch up
+			}
+			timer.Stop()
+			timer.Reset(time.Until(next))
+		}
 		for {
 			select {
-			case t := <-ticker.C:
-				ch <- s.sample(t)
+			case t := <-timer.C:
+				samp := s.sample(t)
+				ch <- samp
+				if s.Adaptive {
+					if d := s.adapt(samp); d != s.Interval {
+						s.Interval = d
+					}
+				}
+				reschedule(next)
+			case d := <-s.intervalCh:
+				// Applied on the sampling goroutine so rate math never races
+				s.Interval = d
+				s.baseInterval = d
+				s.stableTicks = 0
+				reschedule(time.Now())
 			case <-ctx.Done():
 				return
 			}
@@ -78,41 +282,216 @@ func (s *Sampler) Stream(ctx context.Context) <-chan model.Sample {
 	return ch
 }
 
+// nextDelay is the interval moved by a random amount within ±Jitter.
+func (s *Sampler) nextDelay() time.Duration {
+	j := min(s.Jitter, s.Interval/2)
+	if j <= 0 {
+		return s.Interval
+	}
+	return s.Interval - j + time.Duration(rand.Int63n(int64(2*j)+1))
+}
+
+// elapsedSeconds is the time since the previous sample, which rates are
+// computed over; the interval stands in before there is one.
+func (s *Sampler) elapsedSeconds() float64 {
+	if s.elapsed <= 0 {
+		return s.Interval.Seconds()
+	}
+	return s.elapsed.Seconds()
+}
+
+// recoverPanic records a panic on a sampling goroutine and stops the stream,
+// so the caller can restore the terminal and report it rather than the
+// process dying with the TUI still in raw mode.
+func (s *Sampler) recoverPanic(stop context.CancelFunc) {
+	if r := recover(); r != nil {
+		s.errMu.Lock()
+		if s.err == nil {
+			s.err = fmt.Errorf("sampler panic: %v\n%s", r, debug.Stack())
+		}
+		s.errMu.Unlock()
+		stop()
+	}
+}
+
+// Err is the panic that ended the stream, nil while it is still running or
+// after a clean stop.
+func (s *Sampler) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// adapt returns the interval for the next tick: doubled (up to
+// adaptiveMaxFactor x base) after adaptiveStableTicks quiet samples, and back
+// to base as soon as anything moves.
+func (s *Sampler) adapt(cur model.Sample) time.Duration {
+	prev := s.adaptPrev
+	s.adaptPrev = &cur
+	if prev == nil || !quietSince(*prev, cur) {
+		s.stableTicks = 0
+		return s.baseInterval
+	}
+	s.stableTicks++
+	if s.stableTicks < adaptiveStableTicks {
+		return s.Interval
+	}
+	s.stableTicks = 0
+	return min(s.Interval*2, s.baseInterval*adaptiveMaxFactor)
+}
+
+// quietSince reports whether CPU, IO and the leading processes are unchanged
+// enough between two samples to sample less often.
+func quietSince(a, b model.Sample) bool {
+	near := func(x, y, tol float64) bool { return x-y < tol && y-x < tol }
+	if !near(a.CPU.Total, b.CPU.Total, 5) ||
+		!near(a.IO.DiskReadMBs+a.IO.DiskWriteMBs, b.IO.DiskReadMBs+b.IO.DiskWriteMBs, 1) ||
+		!near(a.IO.NetRxMbps+a.IO.NetTxMbps, b.IO.NetRxMbps+b.IO.NetTxMbps, 1) {
+		return false
+	}
+	// Same set of busy leading processes: a new hog shows up here right away.
+	// Near-idle ones are ignored since their order churns on noise.
+	busy := func(top []model.Process) map[int]bool {
+		set := make(map[int]bool)
+		for i := 0; i < len(top) && i < 5; i++ {
+			if top[i].CPU >= 1 {
+				set[top[i].PID] = true
+			}
+		}
+		return set
+	}
+	ba, bb := busy(a.Top), busy(b.Top)
+	if len(ba) != len(bb) {
+		return false
+	}
+	for pid := range ba {
+		if !bb[pid] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetNetStates turns the TCP/UDP socket state tally on or off.
+func (s *Sampler) SetNetStates(on bool) { s.netStatesOn.Store(on) }
+
+// SetGPU starts or stops GPU polling; while off no GPU tool is run and the
+// sample carries no GPUs.
+func (s *Sampler) SetGPU(on bool) {
+	s.gpuOn.Store(on)
+	if !on {
+		s.gpuMu.Lock()
+		s.gpuData, s.gpuProcs = nil, nil
+		s.gpuMu.Unlock()
+	}
+}
+
+// SetBattery turns battery reads on or off.
+func (s *Sampler) SetBattery(on bool) { s.battOn.Store(on) }
+
+// SetKernelThreads includes or leaves out kernel threads.
+func (s *Sampler) SetKernelThreads(on bool) { s.kthreadsOn.Store(on) }
+
+// SetPinned replaces the set of PIDs kept in Top even when they fall outside
+// the TopN cut.
+func (s *Sampler) SetPinned(pids []int) {
+	set := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		set[pid] = true
+	}
+	s.pinMu.Lock()
+	s.pinned = set
+	s.pinMu.Unlock()
+}
+
+// SetFocus keeps process pid, every process whose name matches the glob
+// name (see model.Process.MatchesName) and their descendants in Top, so a
+// focused process tree can be followed however it ranks. Zero and "" clear.
+func (s *Sampler) SetFocus(pid int, name string) {
+	s.pinMu.Lock()
+	s.focusPID, s.focusName = pid, name
+	s.pinMu.Unlock()
+}
+
+// SetInterval changes the sampling period of a running Stream. Non-positive
+// durations are ignored; a pending change not yet applied is replaced.
+func (s *Sampler) SetInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	for {
+		select {
+		case s.intervalCh <- d:
+			return
+		default:
+			select {
+			case <-s.intervalCh:
+			default:
+			}
+		}
+	}
+}
+
 func (s *Sampler) sample(now time.Time) model.Sample {
+	if !s.sampledAt.IsZero() {
+		s.elapsed = now.Sub(s.sampledAt)
+	}
+	s.sampledAt = now
+
 	memStat, _ := mem.VirtualMemory()
 	swapStat, _ := mem.SwapMemory()
+	swapIn, swapOut := s.swapRates()
 
-	cpuPct, corePct := s.cpuPercents()
+	cpuStat := s.cpuPercents()
 	loadAvg, _ := load.Avg()
 
 	ioStat := s.ioNet()
+	var netStates *model.NetStates
+	if s.netStatesOn.Load() {
+		netStates = s.plat.netStates()
+	}
 
 	// Clear cgroup cache occasionally (every ~60 ticks) to handle PID reuse
 	s.cacheTick++
 	if s.cacheTick > 60 {
-		s.cgroupCache = make(map[int]string)
+		s.cgroupCache = make(map[int]cgroupRef)
 		s.cacheTick = 0
 	}
-	top, throttled, cgroups := s.topProcs()
+	top, throttled, stuck, cgroups, units, containers, tasks := s.topProcs()
+	var system model.System
+	if ks, ok := s.plat.kernelStat(); ok {
+		tasks.Running, tasks.Blocked = ks.running, ks.blocked
+		system = s.systemRates(ks)
+	}
 
 	s.gpuMu.RLock()
 	gpus := s.gpuData
+	gpuProcs := s.gpuProcs
 	s.gpuMu.RUnlock()
 
-	batt := s.battery()
+	var batt model.Battery
+	if s.battOn.Load() {
+		batt = s.plat.battery()
+	}
 	inotify := s.inotify()
-	temps := s.temps()
+	temps := s.plat.temps()
 
 	return model.Sample{
-		Timestamp: now,
-		Interval:  s.Interval,
+		SchemaVersion: model.SchemaVersion,
+		Timestamp:     now,
+		Interval:      s.Interval,
 		CPU: model.CPU{
-			Total:   cpuPct,
-			PerCore: corePct,
+			Total:   cpuStat.Total,
+			PerCore: cpuStat.PerCore,
+			IOWait:  cpuStat.IOWait,
+			Steal:   cpuStat.Steal,
+			Guest:   cpuStat.Guest,
 			Load1:   loadAvg.Load1,
 			Load5:   loadAvg.Load5,
 			Load15:  loadAvg.Load15,
 		},
+		Tasks:  tasks,
+		System: system,
 		Memory: model.Memory{
 			UsedBytes:  memStat.Used,
 			TotalBytes: memStat.Total,
@@ -120,38 +499,58 @@ func (s *Sampler) sample(now time.Time) model.Sample {
 			SwapTotal:  swapStat.Total,
 			Cached:     memStat.Cached,
 			Buffers:    memStat.Buffers,
+
+			// gopsutil already parses these out of /proc/meminfo
+			Slab:           memStat.Slab,
+			Dirty:          memStat.Dirty,
+			Writeback:      memStat.WriteBack,
+			HugePagesTotal: memStat.HugePagesTotal,
+			HugePagesFree:  memStat.HugePagesFree,
+			HugePageSize:   memStat.HugePageSize,
+
+			SwapInBps:  swapIn,
+			SwapOutBps: swapOut,
 		},
-		IO:        ioStat,
-		GPUs:      gpus,
-		Battery:   batt,
-		Top:       top,
-		Throttled: throttled,
-		Cgroups:   cgroups,
-		Inotify:   inotify,
-		Temps:     temps,
+		Zram:       s.plat.zram(),
+		IO:         ioStat,
+		NetStates:  netStates,
+		GPUs:       gpus,
+		GPUProcs:   gpuProcs,
+		Battery:    batt,
+		Top:        top,
+		Throttled:  throttled,
+		Stuck:      stuck,
+		Cgroups:    cgroups,
+		Units:      units,
+		Containers: containers,
+		Inotify:    inotify,
+		Limits:     s.plat.limits(),
+		Access:     s.access,
+		Temps:      temps,
 	}
 }
 
-// CPU percentages from times delta.
-func (s *Sampler) cpuPercents() (total float64, perCore []float64) {
+// CPU percentages from times delta; load averages are left for the caller.
+func (s *Sampler) cpuPercents() (out model.CPU) {
 	times, _ := cpu.Times(false)
 	if len(times) == 0 {
-		return 0, nil
+		return out
 	}
 	cur := times[0]
-	curTotal := cur.Total()
-	curIdle := cur.Idle + cur.Iowait
-	if s.prevTotal > 0 {
-		dt := curTotal - s.prevTotal
-		di := curIdle - s.prevIdle
+	if prev := s.prevCPU; prev != nil {
+		dt := cur.Total() - prev.Total()
+		share := func(curV, prevV float64) float64 { return max(0, 100*(curV-prevV)/dt) }
 		if dt > 0 {
-			total = 100 * (1 - di/dt)
+			out.Total = 100 - share(cur.Idle+cur.Iowait, prev.Idle+prev.Iowait)
+			out.IOWait = share(cur.Iowait, prev.Iowait)
+			out.Steal = share(cur.Steal, prev.Steal)
+			out.Guest = share(cur.Guest+cur.GuestNice, prev.Guest+prev.GuestNice)
 		}
 	}
-	s.prevTotal, s.prevIdle = curTotal, curIdle
+	s.prevCPU = &cur
 
 	coreTimes, _ := cpu.Times(true)
-	perCore = make([]float64, len(coreTimes))
+	perCore := make([]float64, len(coreTimes))
 	for i, c := range coreTimes {
 		if i >= len(s.prevCore) {
 			perCore[i] = 0
@@ -165,7 +564,47 @@ func (s *Sampler) cpuPercents() (total float64, perCore []float64) {
 		}
 	}
 	s.prevCore = coreTimes
-	return
+	out.PerCore = perCore
+	return out
+}
+
+// systemRates turns the cumulative /proc/stat counters into per-second rates
+// against the previous reading.
+func (s *Sampler) systemRates(ks kernelStat) model.System {
+	prev := s.prevKstat
+	s.prevKstat = &ks
+	if prev == nil || ks.ctxt < prev.ctxt || ks.intr < prev.intr || ks.forks < prev.forks {
+		return model.System{}
+	}
+	dur := s.elapsedSeconds()
+	if dur <= 0 {
+		dur = 1
+	}
+	return model.System{
+		CtxSwitches: float64(ks.ctxt-prev.ctxt) / dur,
+		Interrupts:  float64(ks.intr-prev.intr) / dur,
+		Forks:       float64(ks.forks-prev.forks) / dur,
+	}
+}
+
+// swapRates turns the cumulative swap page counters into bytes/s in and out
+// against the previous reading.
+func (s *Sampler) swapRates() (in, out float64) {
+	pin, pout, ok := s.plat.swapPages()
+	if !ok {
+		return 0, 0
+	}
+	prev := s.prevSwap
+	s.prevSwap = &[2]uint64{pin, pout}
+	if prev == nil || pin < prev[0] || pout < prev[1] {
+		return 0, 0
+	}
+	dur := s.elapsedSeconds()
+	if dur <= 0 {
+		dur = 1
+	}
+	page := float64(os.Getpagesize())
+	return float64(pin-prev[0]) * page / dur, float64(pout-prev[1]) * page / dur
 }
 
 func (s *Sampler) ioNet() model.IO {
@@ -173,8 +612,9 @@ func (s *Sampler) ioNet() model.IO {
 	diskCounters, _ := disk.IOCounters()
 	var rdBytesDelta, wrBytesDelta uint64
 	var perDev []model.IODevice
+	driveTemps := s.plat.diskTemps()
 	for name, st := range diskCounters {
-		if strings.HasPrefix(name, "loop") {
+		if strings.HasPrefix(name, "loop") || !matchDevice(name, s.DiskInclude, s.DiskExclude) {
 			continue
 		}
 		prev, ok := s.prevDisk[name]
@@ -185,7 +625,7 @@ func (s *Sampler) ioNet() model.IO {
 			if st.WriteBytes > prev.WriteBytes {
 				wrBytesDelta += st.WriteBytes - prev.WriteBytes
 			}
-			dt := s.Interval.Seconds()
+			dt := s.elapsedSeconds()
 			if dt <= 0 {
 				dt = 1
 			}
@@ -193,11 +633,12 @@ func (s *Sampler) ioNet() model.IO {
 				Name:     name,
 				ReadMBs:  float64(st.ReadBytes-prev.ReadBytes) / (1024 * 1024) / dt,
 				WriteMBs: float64(st.WriteBytes-prev.WriteBytes) / (1024 * 1024) / dt,
+				TempC:    driveTemp(driveTemps, name),
 			})
 		}
 		s.prevDisk[name] = st
 	}
-	dur := s.Interval.Seconds()
+	dur := s.elapsedSeconds()
 	if dur <= 0 {
 		dur = 1
 	}
@@ -208,33 +649,73 @@ func (s *Sampler) ioNet() model.IO {
 	}
 
 	// Net
-	netCounters, _ := net.IOCounters(false)
-	if len(netCounters) > 0 && len(s.prevNet) > 0 {
-		rx := netCounters[0].BytesRecv - s.prevNet[0].BytesRecv
-		tx := netCounters[0].BytesSent - s.prevNet[0].BytesSent
-		ioStat.NetRxMbps = float64(rx*8) / 1e6 / dur
-		ioStat.NetTxMbps = float64(tx*8) / 1e6 / dur
+	total, perNIC, ok := s.netCounters()
+	if !ok {
+		return ioStat
 	}
-	if len(netCounters) > 0 {
-		s.prevNet = netCounters
+	if prev := s.prevNet; prev != nil {
+		// Filtered sums can shrink when an interface goes away
+		if total.BytesRecv >= prev.BytesRecv && total.BytesSent >= prev.BytesSent {
+			ioStat.NetRxMbps = float64((total.BytesRecv-prev.BytesRecv)*8) / 1e6 / dur
+			ioStat.NetTxMbps = float64((total.BytesSent-prev.BytesSent)*8) / 1e6 / dur
+		}
 	}
+	prevNIC := s.prevNIC
+	s.prevNIC = make(map[string]net.IOCountersStat, len(perNIC))
+	for _, c := range perNIC {
+		s.prevNIC[c.Name] = c
+		prev, ok := prevNIC[c.Name]
+		if !ok || c.BytesRecv < prev.BytesRecv || c.BytesSent < prev.BytesSent {
+			continue
+		}
+		ioStat.PerNIC = append(ioStat.PerNIC, model.NetIface{
+			Name:   c.Name,
+			RxMbps: float64((c.BytesRecv-prev.BytesRecv)*8) / 1e6 / dur,
+			TxMbps: float64((c.BytesSent-prev.BytesSent)*8) / 1e6 / dur,
+		})
+	}
+	sort.Slice(ioStat.PerNIC, func(i, j int) bool { return ioStat.PerNIC[i].Name < ioStat.PerNIC[j].Name })
+	s.prevNet = &total
 	return ioStat
 }
 
-func (s *Sampler) topProcs() (top []model.Process, throttled []model.Process, cgs []model.Cgroup) {
+func (s *Sampler) topProcs() (top []model.Process, throttled []model.Process, stuck []model.Process, cgs []model.Cgroup, units []model.Unit, containers []model.Container, tasks model.Tasks) {
 	procs, _ := process.Processes()
-	type cgAgg struct{ cpu float64 }
+	tasks.Processes = len(procs)
+	s.access = model.Access{}
+	type cgAgg struct {
+		cpu  float64
+		path string
+	}
 	cgMap := make(map[string]*cgAgg)
+	unitMap := make(map[string]*model.Unit)
+	ctrMap := make(map[string]*model.Container)
 	newProcIO := make(map[int]procIO)
-	dt := s.Interval.Seconds()
+	newFD := make(map[int]int)
+	newRSS := make(map[int]uint64)
+	newFaults := make(map[int]faults)
+	newBlkio := make(map[int]float64)
+	dt := s.elapsedSeconds()
 	if dt <= 0 {
 		dt = 1
 	}
+	cores, _ := cpu.Counts(true)
+	if cores <= 0 {
+		cores = 1
+	}
+	kthreads := s.kthreadsOn.Load()
+	handles := make(map[int]*process.Process) // Light: read FDs/IO after the cut
 
 	for _, p := range procs {
 		// Skip kernel threads without name
 		name, _ := p.Name()
 		if name == "" {
+			tasks.Threads++
+			continue
+		}
+		ppid, _ := p.Ppid()
+		if !kthreads && s.plat.kernelThread(p.Pid, ppid) {
+			tasks.Threads++ // kernel threads are single-threaded
 			continue
 		}
 		cpuPct, _ := p.CPUPercent()
@@ -244,54 +725,139 @@ func (s *Sampler) topProcs() (top []model.Process, throttled []model.Process, cg
 		if cmd == "" {
 			cmd = name
 		}
-		fdCount, _ := p.NumFDs()
-		fdDiff := int(fdCount) - s.prevFD[int(p.Pid)]
-
-		var rRate, wRate float64
-		if ioCounters, err := p.IOCounters(); err == nil && ioCounters != nil {
-			prev := s.prevProcIO[int(p.Pid)]
-			if prev.read > 0 && ioCounters.ReadBytes >= prev.read && dt > 0 {
-				rRate = float64(ioCounters.ReadBytes-prev.read) / 1024.0 / dt
+		cmd = s.Redact.Command(cmd)
+		var rss uint64
+		var memDiff int64
+		if mi, err := p.MemoryInfo(); err == nil && mi != nil {
+			rss = mi.RSS
+			if prev, ok := s.prevRSS[int(p.Pid)]; ok {
+				memDiff = int64(rss) - int64(prev)
 			}
-			if prev.write > 0 && ioCounters.WriteBytes >= prev.write && dt > 0 {
-				wRate = float64(ioCounters.WriteBytes-prev.write) / 1024.0 / dt
+			newRSS[int(p.Pid)] = rss
+		}
+
+		var minRate, majRate float64
+		if pf, err := p.PageFaults(); err == nil && pf != nil {
+			if prev, ok := s.prevFaults[int(p.Pid)]; ok {
+				if pf.MinorFaults >= prev.minor {
+					minRate = float64(pf.MinorFaults-prev.minor) / dt
+				}
+				if pf.MajorFaults >= prev.major {
+					majRate = float64(pf.MajorFaults-prev.major) / dt
+				}
 			}
-			newProcIO[int(p.Pid)] = procIO{read: ioCounters.ReadBytes, write: ioCounters.WriteBytes}
+			newFaults[int(p.Pid)] = faults{minor: pf.MinorFaults, major: pf.MajorFaults}
 		}
 
 		entry := model.Process{
-			PID:      int(p.Pid),
-			Nice:     int(nice),
-			CPU:      cpuPct,
-			Memory:   float64(memPct),
-			Command:  truncate(cmd, 60),
-			FDCount:  int(fdCount),
-			ReadKBs:  rRate,
-			WriteKBs: wRate,
-			FDDiff:   fdDiff,
+			PID:     int(p.Pid),
+			PPID:    int(ppid),
+			Nice:    s.plat.nice(nice),
+			CPU:     cpuPct,
+			CPUNorm: cpuPct / float64(cores),
+			Memory:  float64(memPct),
+			Command: cmd,
+			MemDiff: memDiff,
+
+			Comm: name,
+
+			MinorFaults: minRate,
+			MajorFaults: majRate,
+		}
+		entry.Exe, _ = p.Exe()
+		if t, err := p.Times(); err == nil && t != nil {
+			entry.CPUTimeSeconds = t.User + t.System
+		}
+		threads, _ := p.NumThreads()
+		entry.Threads = int(threads)
+		tasks.Threads += max(entry.Threads, 1)
+		if st, err := p.Status(); err == nil {
+			entry.State = stateLetter(st)
+		}
+		switch entry.State {
+		case "R":
+			tasks.Running++
+		case "D":
+			tasks.Blocked++
+			tasks.Stuck++
+		}
+		if uids, err := p.Uids(); err == nil && len(uids) > 0 {
+			entry.User = s.username(uids[0])
+		}
+		entry.OOMScore, entry.OOMScoreAdj = s.plat.oomScore(int(p.Pid))
+		if s.Light {
+			handles[entry.PID] = p
+		} else {
+			s.readFDIO(p, &entry, dt, newFD, newProcIO, newBlkio)
+		}
+		// Best-effort cgroup aggregation by the last path component
+		if ref, err := s.readProcCgroup(int(p.Pid)); err == nil {
+			if _, ok := cgMap[ref.name]; !ok {
+				cgMap[ref.name] = &cgAgg{path: ref.path}
+			}
+			cgMap[ref.name].cpu += cpuPct
+			if ref.unit != "" {
+				u, ok := unitMap[ref.unit]
+				if !ok {
+					u = &model.Unit{Name: ref.unit}
+					unitMap[ref.unit] = u
+				}
+				u.CPU += cpuPct
+				u.Procs++
+				u.MemBytes += rss
+			}
+			if id := containerID(ref.path); id != "" {
+				c, ok := ctrMap[id]
+				if !ok {
+					c = &model.Container{Name: s.containerName(id), ID: id}
+					ctrMap[id] = c
+				}
+				entry.Container = c.Name
+				c.CPU += cpuPct
+				c.Procs++
+				c.MemBytes += rss
+			}
 		}
 		top = append(top, entry)
-		if nice > 0 {
+		if entry.Nice > 0 {
 			throttled = append(throttled, entry)
 		}
-		// cgroup aggregate (best-effort)
-		// Best-effort cgroup aggregation: parse /proc/<pid>/cgroup last path component.
-		if cgPath, err := s.readProcCgroup(int(p.Pid)); err == nil {
-			if _, ok := cgMap[cgPath]; !ok {
-				cgMap[cgPath] = &cgAgg{}
-			}
-			cgMap[cgPath].cpu += cpuPct
+		if entry.State == "D" {
+			stuck = append(stuck, entry)
 		}
 	}
 
 	sort.Slice(top, func(i, j int) bool { return top[i].CPU > top[j].CPU })
-	if len(top) > 64 {
-		top = top[:64]
+	if n := s.TopN; n > 0 && len(top) > n {
+		s.pinMu.Lock()
+		focused := focusTree(top, s.focusPID, s.focusName)
+		s.pinMu.Unlock()
+		rest := top[n:]
+		top = append(top[:n:n], fastestGrowing(rest, growthExtra)...)
+		top = append(top, extraBy(rest, top, lifetimeExtra, func(p model.Process) float64 { return p.CPUTimeSeconds })...)
+		top = append(top, extraBy(rest, top, faultExtra, func(p model.Process) float64 { return p.MajorFaults })...)
+		s.pinMu.Lock()
+		for _, p := range rest {
+			if (s.pinned[p.PID] || focused[p.PID]) && !containsPID(top, p.PID) {
+				top = append(top, p)
+			}
+		}
+		s.pinMu.Unlock()
 	}
 	sort.Slice(throttled, func(i, j int) bool { return throttled[i].CPU > throttled[j].CPU })
-	if len(throttled) > 32 {
-		throttled = throttled[:32]
+	if n := s.ThrottledN; n > 0 && len(throttled) > n {
+		throttled = throttled[:n]
 	}
+	if s.Light {
+		// The IO delay that orders Stuck is only known once it has been read
+		s.readKept([][]model.Process{top, throttled, stuck}, handles, dt, newFD, newProcIO, newBlkio)
+	}
+	// Longest-blocked first: the IO delay shows who has been waiting longest
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].IOWaitMs > stuck[j].IOWaitMs })
+	if len(stuck) > 32 {
+		stuck = stuck[:32]
+	}
+	s.fillConns(top)
 
 	for name, agg := range cgMap {
 		cgs = append(cgs, model.Cgroup{Name: name, CPU: agg.cpu})
@@ -300,21 +866,290 @@ func (s *Sampler) topProcs() (top []model.Process, throttled []model.Process, cg
 	if len(cgs) > 16 {
 		cgs = cgs[:16]
 	}
+	// Accounting files are only read for the cgroups we keep
+	for i := range cgs {
+		s.plat.cgroupStats(&cgs[i], cgMap[cgs[i].Name].path)
+	}
 
-	s.prevProcIO = newProcIO
-	s.prevFD = make(map[int]int)
-	for _, p := range top {
-		s.prevFD[p.PID] = p.FDCount
+	for _, u := range unitMap {
+		units = append(units, *u)
 	}
+	sort.Slice(units, func(i, j int) bool { return units[i].CPU > units[j].CPU })
+	if len(units) > 32 {
+		units = units[:32]
+	}
+
+	for _, c := range ctrMap {
+		containers = append(containers, *c)
+	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].CPU > containers[j].CPU })
+	if len(containers) > 32 {
+		containers = containers[:32]
+	}
+
+	s.prevProcIO = newProcIO
+	s.prevFD = newFD
+	s.prevRSS = newRSS
+	s.prevFaults = newFaults
+	s.prevBlkio = newBlkio
 	return
 }
 
+// readFDIO fills the FD count, IO rates and blkio delay, the readdir- and
+// file-heavy part of a process read, recording the counters for the next
+// sample's deltas.
+func (s *Sampler) readFDIO(p *process.Process, e *model.Process, dt float64, newFD map[int]int, newProcIO map[int]procIO, newBlkio map[int]float64) {
+	s.access.Checked++
+	fdCount, err := p.NumFDs()
+	if e.FDDenied = errors.Is(err, fs.ErrPermission); e.FDDenied {
+		s.access.FDDenied++
+	}
+	// Deltas are kept for every process read so a newcomer to the top list
+	// doesn't report its whole footprint as growth
+	if prev, ok := s.prevFD[e.PID]; ok {
+		e.FDDiff = int(fdCount) - prev
+	}
+	e.FDCount = int(fdCount)
+	newFD[e.PID] = int(fdCount)
+
+	var rRate, wRate float64
+	var rTotal, wTotal uint64
+	ioCounters, err := p.IOCounters()
+	if e.IODenied = errors.Is(err, fs.ErrPermission); e.IODenied {
+		s.access.IODenied++
+	}
+	if err == nil && ioCounters != nil {
+		prev := s.prevProcIO[int(p.Pid)]
+		if prev.read > 0 && ioCounters.ReadBytes >= prev.read && dt > 0 {
+			rRate = float64(ioCounters.ReadBytes-prev.read) / 1024.0 / dt
+		}
+		if prev.write > 0 && ioCounters.WriteBytes >= prev.write && dt > 0 {
+			wRate = float64(ioCounters.WriteBytes-prev.write) / 1024.0 / dt
+		}
+		newProcIO[int(p.Pid)] = procIO{read: ioCounters.ReadBytes, write: ioCounters.WriteBytes}
+		rTotal, wTotal = ioCounters.ReadBytes, ioCounters.WriteBytes
+	}
+	e.ReadKBs, e.WriteKBs = rRate, wRate
+	e.ReadTotal, e.WriteTotal = rTotal, wTotal
+
+	if ms, ok := s.plat.blkioDelay(e.PID); ok {
+		if prev, seen := s.prevBlkio[e.PID]; seen && ms >= prev {
+			e.IOWaitMs = (ms - prev) / dt
+		}
+		newBlkio[e.PID] = ms
+	}
+}
+
+// readKept runs readFDIO for the processes that survived the list cuts, once
+// per PID even when it is in more than one of Top, Throttled and Stuck.
+func (s *Sampler) readKept(lists [][]model.Process, handles map[int]*process.Process, dt float64, newFD map[int]int, newProcIO map[int]procIO, newBlkio map[int]float64) {
+	read := make(map[int]model.Process, len(lists[0]))
+	for _, list := range lists {
+		for i := range list {
+			if r, ok := read[list[i].PID]; ok {
+				list[i] = r
+				continue
+			}
+			if p, ok := handles[list[i].PID]; ok {
+				s.readFDIO(p, &list[i], dt, newFD, newProcIO, newBlkio)
+				read[list[i].PID] = list[i]
+			}
+		}
+	}
+}
+
+// focusTree returns the PIDs of process pid, the processes matching the
+// name glob and all their descendants in procs; nil when neither is set.
+func focusTree(procs []model.Process, pid int, name string) map[int]bool {
+	if pid <= 0 && name == "" {
+		return nil
+	}
+	children := make(map[int][]int)
+	var queue []int
+	for _, p := range procs {
+		children[p.PPID] = append(children[p.PPID], p.PID)
+		if p.PID == pid || (name != "" && p.MatchesName(name)) {
+			queue = append(queue, p.PID)
+		}
+	}
+	tree := make(map[int]bool)
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if tree[pid] {
+			continue
+		}
+		tree[pid] = true
+		queue = append(queue, children[pid]...)
+	}
+	return tree
+}
+
+func containsPID(procs []model.Process, pid int) bool {
+	for _, p := range procs {
+		if p.PID == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// fastestGrowing picks up to n processes from rest with the largest positive
+// RSS or FD growth, so leaks below the CPU cut still reach the delta sorts.
+func fastestGrowing(rest []model.Process, n int) []model.Process {
+	byMem := append([]model.Process{}, rest...)
+	sort.Slice(byMem, func(i, j int) bool { return byMem[i].MemDiff > byMem[j].MemDiff })
+	byFD := append([]model.Process{}, rest...)
+	sort.Slice(byFD, func(i, j int) bool { return byFD[i].FDDiff > byFD[j].FDDiff })
+
+	var out []model.Process
+	seen := make(map[int]bool)
+	for i := 0; i < len(rest) && len(out) < n; i++ {
+		if p := byMem[i]; p.MemDiff > 0 && !seen[p.PID] {
+			seen[p.PID] = true
+			out = append(out, p)
+		}
+		if p := byFD[i]; p.FDDiff > 0 && !seen[p.PID] && len(out) < n {
+			seen[p.PID] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// extraBy picks up to n processes from rest, not already in top, with the
+// largest positive key, so processes below the CPU cut still reach views
+// that rank by it (lifetime Hall of Shame, major-fault sort).
+func extraBy(rest, top []model.Process, n int, key func(model.Process) float64) []model.Process {
+	byKey := append([]model.Process{}, rest...)
+	sort.Slice(byKey, func(i, j int) bool { return key(byKey[i]) > key(byKey[j]) })
+	var out []model.Process
+	for _, p := range byKey {
+		if len(out) >= n || key(p) <= 0 {
+			break
+		}
+		if !containsPID(top, p.PID) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// fillConns sets socket counts on the leading CPU consumers, reusing the
+// cached counts between refreshes.
+func (s *Sampler) fillConns(top []model.Process) {
+	n := len(top)
+	if n > connSampleTop {
+		n = connSampleTop
+	}
+	if s.connTick%connRefreshTicks == 0 {
+		fresh := make(map[int]int, n)
+		for _, p := range top[:n] {
+			fresh[p.PID] = s.plat.sockets(p.PID)
+		}
+		s.connCache = fresh
+	}
+	s.connTick++
+	for i := range top[:n] {
+		if c, ok := s.connCache[top[i].PID]; ok {
+			top[i].Conns = c
+		}
+	}
+}
+
+// Inspect reads the full command line, executable and environment of pid.
+// Fields the caller may not read (other users' processes) are left empty.
+func Inspect(pid int) model.ProcInfo {
+	info := model.ProcInfo{PID: pid}
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return info
+	}
+	info.Cmdline, _ = p.Cmdline()
+	info.Exe, _ = p.Exe()
+	env, _ := p.Environ()
+	for _, kv := range env {
+		if kv == "" {
+			continue
+		}
+		if k, _, ok := strings.Cut(kv, "="); ok && looksSecret(k) {
+			kv = k + "=<redacted>"
+		}
+		info.Env = append(info.Env, kv)
+	}
+	sort.Strings(info.Env)
+	return info
+}
+
+// stateLetters maps gopsutil's status names back to ps state letters.
+var stateLetters = map[string]string{
+	process.Running: "R",
+	process.Sleep:   "S",
+	process.Blocked: "D",
+	process.Zombie:  "Z",
+	process.Stop:    "T",
+	process.Idle:    "I",
+	process.Wait:    "W",
+	process.Lock:    "L",
+}
+
+func stateLetter(status []string) string {
+	if len(status) == 0 {
+		return ""
+	}
+	if l, ok := stateLetters[status[0]]; ok {
+		return l
+	}
+	return "?"
+}
+
+// Alive reports whether pid still exists.
+func Alive(pid int) bool {
+	ok, _ := process.PidExists(int32(pid))
+	return ok
+}
+
+var errNoUIDs = errors.New("user IDs unavailable")
+
+// UIDs returns pid's real and effective user IDs.
+func UIDs(pid int) (ruid, euid int, err error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	ids, err := p.Uids()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(ids) < 2 {
+		return 0, 0, errNoUIDs
+	}
+	return int(ids[0]), int(ids[1]), nil
+}
+
+// looksSecret matches env var names that commonly carry credentials.
+func looksSecret(name string) bool {
+	name = strings.ToUpper(name)
+	for _, s := range []string{"TOKEN", "SECRET", "PASS", "KEY", "AUTH", "CREDENTIAL", "COOKIE", "SESSION"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Sampler) gpuLoop(ctx context.Context) {
 	// Initial fetch
-	s.updateGPU()
+	if s.gpuOn.Load() {
+		s.updateGPU()
+	}
 
 	// Poll GPU slower than main loop to reduce overhead/stutter
-	ticker := time.NewTicker(2 * time.Second)
+	every := s.GPUInterval
+	if every <= 0 {
+		every = DefaultGPUInterval
+	}
+	ticker := time.NewTicker(every)
 	defer ticker.Stop()
 
 	for {
@@ -322,22 +1157,42 @@ func (s *Sampler) gpuLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.updateGPU()
+			if s.gpuOn.Load() {
+				s.updateGPU()
+			}
 		}
 	}
 }
 
 func (s *Sampler) updateGPU() {
 	data := s.queryGPU()
+	var procs []model.GPUProcess
+	if len(data) > 0 {
+		procs = s.queryGPUProcs()
+	}
+	data = append(data, queryIntelGPU()...)
 	s.gpuMu.Lock()
+	defer s.gpuMu.Unlock()
+	if !s.gpuOn.Load() {
+		return // turned off while the tools ran
+	}
 	s.gpuData = data
-	s.gpuMu.Unlock()
+	s.gpuProcs = procs
 }
 
+// gpuQuery is the nvidia-smi field list; gpuQueryBasic is the fallback for
+// drivers that reject a field (the whole query then fails).
+const (
+	gpuQueryBasic = "name,utilization.gpu,memory.used,memory.total,temperature.gpu"
+	gpuQuery      = gpuQueryBasic + ",power.draw,power.limit,clocks.sm,clocks.mem"
+)
+
 func (s *Sampler) queryGPU() []model.GPU {
-	out, _ := runCmd(400*time.Millisecond, "nvidia-smi",
-		"--query-gpu=name,utilization.gpu,memory.used,memory.total,temperature.gpu",
-		"--format=csv,noheader,nounits")
+	out, err := runCmd(400*time.Millisecond, "nvidia-smi", "--query-gpu="+gpuQuery, "--format=csv,noheader,nounits")
+	if err != nil && !errors.Is(err, exec.ErrNotFound) && !errors.Is(err, context.DeadlineExceeded) {
+		// The output is nvidia-smi's complaint about a field, not GPUs
+		out, _ = runCmd(400*time.Millisecond, "nvidia-smi", "--query-gpu="+gpuQueryBasic, "--format=csv,noheader,nounits")
+	}
 	if out == "" {
 		return nil
 	}
@@ -348,67 +1203,122 @@ func (s *Sampler) queryGPU() []model.GPU {
 		if len(parts) < 5 {
 			continue
 		}
-		name := strings.TrimSpace(parts[0])
-		util := parseFloat(parts[1])
-		memUsed := parseFloat(parts[2])
-		memTotal := parseFloat(parts[3])
-		temp := parseFloat(parts[4])
-		gpus = append(gpus, model.GPU{
-			Name:       name,
-			Util:       util,
-			MemUsedMB:  memUsed,
-			MemTotalMB: memTotal,
-			TempC:      temp,
-		})
+		// Unsupported fields read "[N/A]" (or "[Not Supported]"), which
+		// parseFloat turns into 0
+		g := model.GPU{
+			Name:       strings.TrimSpace(parts[0]),
+			Util:       parseFloat(parts[1]),
+			MemUsedMB:  parseFloat(parts[2]),
+			MemTotalMB: parseFloat(parts[3]),
+			TempC:      parseFloat(parts[4]),
+		}
+		if len(parts) >= 9 {
+			g.PowerW = parseFloat(parts[5])
+			g.PowerLimitW = parseFloat(parts[6])
+			g.FreqMHz = parseFloat(parts[7])
+			g.MemClockMHz = parseFloat(parts[8])
+		}
+		gpus = append(gpus, g)
 	}
 	return gpus
 }
 
-func (s *Sampler) battery() model.Battery {
-	battPaths, _ := filepath.Glob("/sys/class/power_supply/BAT*/capacity")
-	for _, capPath := range battPaths {
-		base := filepath.Dir(capPath)
-		capBytes, err := os.ReadFile(capPath)
+func (s *Sampler) queryGPUProcs() []model.GPUProcess {
+	out, _ := runCmd(400*time.Millisecond, "nvidia-smi",
+		"--query-compute-apps=pid,used_memory,process_name",
+		"--format=csv,noheader,nounits")
+	if out == "" {
+		return nil
+	}
+	var procs []model.GPUProcess
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		parts := strings.SplitN(sc.Text(), ",", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(parts[0]))
 		if err != nil {
 			continue
 		}
-		pct := parseFloat(string(capBytes))
-		stateBytes, _ := os.ReadFile(filepath.Join(base, "status"))
-		state := strings.TrimSpace(string(stateBytes))
-		return model.Battery{Percent: pct, State: state}
+		procs = append(procs, model.GPUProcess{
+			PID:       pid,
+			MemUsedMB: parseFloat(parts[1]),
+			Name:      filepath.Base(strings.TrimSpace(parts[2])),
+		})
 	}
-	return model.Battery{}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].MemUsedMB > procs[j].MemUsedMB })
+	return procs
 }
 
 func (s *Sampler) inotify() model.Inotify {
-	readUint := func(path string) uint64 {
-		b, err := os.ReadFile(path)
-		if err != nil {
-			return 0
+	in := s.plat.inotifyLimits()
+	in.Procs = s.inotifyHolders()
+	return in
+}
+
+// inotifyHolders returns the processes holding the most inotify watches,
+// reusing the cached result between refreshes.
+func (s *Sampler) inotifyHolders() []model.InotifyProc {
+	if s.inotifyTick%inotifyRefreshTicks == 0 {
+		s.inotifyProcs = s.plat.inotifyHolders()
+	}
+	s.inotifyTick++
+	return s.inotifyProcs
+}
+
+// netCounters returns the per-interface counters passing
+// NetInclude/NetExclude and their total.
+func (s *Sampler) netCounters() (total net.IOCountersStat, perNIC []net.IOCountersStat, ok bool) {
+	all, err := net.IOCounters(true)
+	if err != nil {
+		return total, nil, false
+	}
+	total.Name = "all"
+	for _, c := range all {
+		if !matchDevice(c.Name, s.NetInclude, s.NetExclude) {
+			continue
 		}
-		v, _ := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
-		return v
+		total.BytesRecv += c.BytesRecv
+		total.BytesSent += c.BytesSent
+		total.PacketsRecv += c.PacketsRecv
+		total.PacketsSent += c.PacketsSent
+		perNIC = append(perNIC, c)
 	}
-	return model.Inotify{
-		MaxUserWatches:   readUint("/proc/sys/fs/inotify/max_user_watches"),
-		MaxUserInstances: readUint("/proc/sys/fs/inotify/max_user_instances"),
-		NrWatches:        readUint("/proc/sys/fs/inotify/nr_watches"),
+	return total, perNIC, true
+}
+
+// matchDevice applies include/exclude glob lists to a device or interface
+// name; malformed patterns never match.
+func matchDevice(name string, include, exclude []string) bool {
+	for _, pat := range exclude {
+		if ok, _ := filepath.Match(pat, name); ok {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
 	}
+	for _, pat := range include {
+		if ok, _ := filepath.Match(pat, name); ok {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *Sampler) temps() []model.Temp {
-	var temps []model.Temp
-	paths, _ := filepath.Glob("/sys/class/thermal/thermal_zone*/temp")
-	for _, p := range paths {
-		b, err := os.ReadFile(p)
-		if err != nil {
-			continue
+// driveTemp looks up a device's temperature, letting partitions (sda1,
+// nvme0n1p2) inherit their disk's reading.
+func driveTemp(temps map[string]float64, name string) float64 {
+	if t, ok := temps[name]; ok {
+		return t
+	}
+	for disk, t := range temps {
+		if rest := strings.TrimPrefix(name, disk); rest != name && strings.Trim(rest, "p0123456789") == "" {
+			return t
 		}
-		val := parseFloat(string(b)) / 1000
-		zone := filepath.Base(filepath.Dir(p))
-		temps = append(temps, model.Temp{Zone: zone, Temp: val})
 	}
-	return temps
+	return 0
 }
 
 // Helpers
@@ -419,11 +1329,16 @@ func parseFloat(s string) float64 {
 	return f
 }
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+func (s *Sampler) username(uid int32) string {
+	if name, ok := s.userCache[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
 	}
-	return s[:max-1] + "…"
+	s.userCache[uid] = name
+	return name
 }
 
 func runCmd(timeout time.Duration, name string, args ...string) (string, error) {
@@ -436,32 +1351,16 @@ func runCmd(timeout time.Duration, name string, args ...string) (string, error)
 	return string(out), err
 }
 
-// readProcCgroup returns the last path component of the first cgroup entry.
-func (s *Sampler) readProcCgroup(pid int) (string, error) {
+// readProcCgroup resolves pid's cgroup through the platform, caching the
+// result until the periodic cache reset.
+func (s *Sampler) readProcCgroup(pid int) (cgroupRef, error) {
 	if v, ok := s.cgroupCache[pid]; ok {
 		return v, nil
 	}
-	path := fmt.Sprintf("/proc/%d/cgroup", pid)
-	f, err := os.Open(path)
+	ref, err := s.plat.procCgroup(pid)
 	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		line := sc.Text()
-		parts := strings.Split(line, ":")
-		if len(parts) != 3 {
-			continue
-		}
-		p := parts[2]
-		segs := strings.Split(p, "/")
-		for i := len(segs) - 1; i >= 0; i-- {
-			if segs[i] != "" {
-				s.cgroupCache[pid] = segs[i]
-				return segs[i], nil
-			}
-		}
+		return cgroupRef{}, err
 	}
-	return "", fmt.Errorf("no cgroup")
+	s.cgroupCache[pid] = ref
+	return ref, nil
 }
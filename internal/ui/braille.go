@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// brailleBits maps a dot within a 2x4 braille cell (column, row from the top)
+// to its bit in the U+2800 block.
+var brailleBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// brailleAxisWidth is the y-label gutter renderBrailleChart puts on the left.
+const brailleAxisWidth = 8
+
+// renderBrailleChart draws values as a color line chart width cells wide and
+// height rows tall, each cell holding 2x4 braille dots. The y axis runs from 0 to
+// the largest value and is labelled at the top, middle and bottom; width
+// includes the label gutter. Points are spread evenly across the plot and
+// consecutive points are joined so the line stays continuous.
+func renderBrailleChart(values []float64, width, height int, color string) string {
+	plotW := maxInt(1, width-brailleAxisWidth)
+	height = maxInt(1, height)
+	values = lastN(values, plotW*2)
+	top := chartTop(values)
+	grid := plotBraille(values, plotW, height, top)
+	lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	return renderBrailleRows(height, top, func(r int) string {
+		return lineStyle.Render(brailleString(grid[r]))
+	})
+}
+
+// renderBrailleOverlay draws two series on one shared y axis, each in its own
+// color; cells where both lines pass are drawn in bothColor.
+func renderBrailleOverlay(a, b []float64, width, height int, colorA, colorB, bothColor string) string {
+	plotW := maxInt(1, width-brailleAxisWidth)
+	height = maxInt(1, height)
+	a, b = lastN(a, plotW*2), lastN(b, plotW*2)
+	top := math.Max(chartTop(a), chartTop(b))
+	gridA := plotBraille(a, plotW, height, top)
+	gridB := plotBraille(b, plotW, height, top)
+	styles := map[string]lipgloss.Style{}
+	style := func(color string) lipgloss.Style {
+		if _, ok := styles[color]; !ok {
+			styles[color] = lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+		}
+		return styles[color]
+	}
+	return renderBrailleRows(height, top, func(r int) string {
+		// Group cells into same-color runs so each run is styled once
+		var out strings.Builder
+		var run []rune
+		runColor := ""
+		for i := range gridA[r] {
+			color := colorA
+			switch {
+			case gridA[r][i] != 0 && gridB[r][i] != 0:
+				color = bothColor
+			case gridB[r][i] != 0:
+				color = colorB
+			}
+			if color != runColor && len(run) > 0 {
+				out.WriteString(style(runColor).Render(brailleString(run)))
+				run = run[:0]
+			}
+			runColor = color
+			run = append(run, gridA[r][i]|gridB[r][i])
+		}
+		if len(run) > 0 {
+			out.WriteString(style(runColor).Render(brailleString(run)))
+		}
+		return out.String()
+	})
+}
+
+// lastN keeps the newest n values.
+func lastN(values []float64, n int) []float64 {
+	if len(values) > n {
+		return values[len(values)-n:]
+	}
+	return values
+}
+
+// chartTop is the y axis maximum for values: their largest, or 1 when flat.
+func chartTop(values []float64) float64 {
+	top := 0.0
+	for _, v := range values {
+		top = math.Max(top, v)
+	}
+	if top <= 0 {
+		top = 1
+	}
+	return top
+}
+
+// brailleString turns dot bits into braille characters.
+func brailleString(cells []rune) string {
+	out := make([]rune, len(cells))
+	for i, c := range cells {
+		out[i] = 0x2800 + c
+	}
+	return string(out)
+}
+
+// renderBrailleRows joins height chart rows, each prefixed with its y label.
+func renderBrailleRows(height int, top float64, row func(r int) string) string {
+	labels := make([]string, height)
+	labels[0] = formatAxis(top)
+	if height > 2 {
+		labels[height/2] = formatAxis(top * float64(height-1-height/2) / float64(height-1))
+	}
+	if height > 1 {
+		labels[height-1] = formatAxis(0)
+	}
+	var b strings.Builder
+	for r := 0; r < height; r++ {
+		b.WriteString(subtleStyle.Render(fmt.Sprintf("%*s ┤", brailleAxisWidth-2, labels[r])))
+		b.WriteString(row(r))
+		if r < height-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// plotBraille returns the dot bits of values drawn as a line on a plotW x
+// height cell grid scaled to top.
+func plotBraille(values []float64, plotW, height int, top float64) [][]rune {
+	dotsW, dotsH := plotW*2, height*4
+	grid := make([][]rune, height)
+	for r := range grid {
+		grid[r] = make([]rune, plotW)
+	}
+	set := func(x, y int) {
+		// y counts dots up from the bottom
+		row := dotsH - 1 - y
+		grid[row/4][x/2] |= brailleBits[x%2][row%4]
+	}
+	dotY := func(v float64) int {
+		return int(math.Round(math.Max(0, v) / top * float64(dotsH-1)))
+	}
+	prevX, prevY := -1, 0
+	for i, v := range values {
+		x := dotsW - len(values) + i // right-aligned, one dot per point
+		if len(values) > 1 && len(values) < dotsW {
+			x = i * (dotsW - 1) / (len(values) - 1)
+		}
+		y := dotY(v)
+		if prevX < 0 {
+			set(x, y)
+		}
+		// Interpolate across the gap, filling vertical runs on steep edges
+		lastY := prevY
+		for px := prevX + 1; prevX >= 0 && px <= x; px++ {
+			t := float64(px-prevX) / float64(x-prevX)
+			py := prevY + int(math.Round(t*float64(y-prevY)))
+			for yy := minInt(py, lastY); yy <= maxInt(py, lastY); yy++ {
+				set(px, yy)
+			}
+			lastY = py
+		}
+		prevX, prevY = x, y
+	}
+	return grid
+}
+
+// formatAxis keeps y labels within the gutter.
+func formatAxis(v float64) string {
+	switch {
+	case v >= 1000:
+		return fmt.Sprintf("%.0fk", v/1000)
+	case v >= 100:
+		return fmt.Sprintf("%.0f", v)
+	case v >= 10:
+		return fmt.Sprintf("%.1f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// trendMetrics are the history series G cycles the Analysis trend chart
+// through. The entry without hist is the net-rx/disk-write overlay.
+var trendMetrics = []struct {
+	name  string
+	unit  string
+	color string
+	hist  func(m *Model) []float64
+}{
+	{"CPU", "%", primaryColor, func(m *Model) []float64 { return m.cpuHist }},
+	{"MEM", "%", "#BD93F9", func(m *Model) []float64 { return m.memHist }},
+	{"NET RX", "Mb/s", successColor, func(m *Model) []float64 { return m.netRxHist }},
+	{"NET TX", "Mb/s", "#0077FF", func(m *Model) []float64 { return m.netTxHist }},
+	{"DISK R", "MB/s", warningColor, func(m *Model) []float64 { return m.diskReadHist }},
+	{"DISK W", "MB/s", secondaryColor, func(m *Model) []float64 { return m.diskWriteHist }},
+	{"NET RX vs DISK W", "MB/s", accentColor, nil},
+}
+
+// renderTrendChart renders the selected metric's history as a braille chart
+// with a title line naming the metric, its latest value and the time span.
+func (m *Model) renderTrendChart(width, height int) string {
+	tm := trendMetrics[m.trendMetric%len(trendMetrics)]
+	if tm.hist == nil {
+		return m.renderPipelineChart(width, height)
+	}
+	hist := tm.hist(m)
+	latest := 0.0
+	if len(hist) > 0 {
+		latest = hist[len(hist)-1]
+	}
+	span := ""
+	if n := len(m.timeHist); n > 1 {
+		span = fmt.Sprintf(" · last %s", m.timeHist[n-1].Sub(m.timeHist[0]).Round(1e9))
+	}
+	title := titleStyle.Background(lipgloss.Color(tm.color)).Render("📈 TREND: "+tm.name) +
+		subtleStyle.Render(fmt.Sprintf(" %.1f %s%s (G: next metric)", latest, tm.unit, span))
+	return lipgloss.JoinVertical(lipgloss.Left, title, renderBrailleChart(hist, width, maxInt(1, height-1), tm.color))
+}
+
+// renderPipelineChart overlays network receive and disk write on one MB/s axis
+// with their correlation over the history window: data arriving and landing
+// on disk together (a download or restore) moves them in step, while write
+// caching or a bottleneck makes them diverge.
+func (m *Model) renderPipelineChart(width, height int) string {
+	rx := make([]float64, len(m.netRxHist))
+	for i, v := range m.netRxHist {
+		rx[i] = v * 1e6 / 8 / mib // Mb/s to the disk's MiB/s
+	}
+	wr := m.diskWriteHist
+	n := minInt(len(rx), len(wr))
+	rx, wr = rx[len(rx)-n:], wr[len(wr)-n:]
+
+	verdict := "waiting for data"
+	if r, ok := correlation(rx, wr); ok {
+		switch {
+		case r >= 0.7:
+			verdict = fmt.Sprintf("r=%+.2f · in step: net→disk pipeline", r)
+		case r >= 0.3:
+			verdict = fmt.Sprintf("r=%+.2f · loosely coupled", r)
+		default:
+			verdict = fmt.Sprintf("r=%+.2f · diverging: caching or a bottleneck", r)
+		}
+	} else if n >= 3 {
+		verdict = "r=n/a · one side is flat"
+	}
+	title := titleStyle.Background(lipgloss.Color(accentColor)).Render("📈 TREND: ") +
+		lipgloss.NewStyle().Foreground(lipgloss.Color(successColor)).Bold(true).Render(" NET RX") +
+		subtleStyle.Render(" vs ") +
+		lipgloss.NewStyle().Foreground(lipgloss.Color(secondaryColor)).Bold(true).Render("DISK W") +
+		subtleStyle.Render(fmt.Sprintf(" MB/s · %s (G: next metric)", verdict))
+	chart := renderBrailleOverlay(rx, wr, width, maxInt(1, height-1), successColor, secondaryColor, "#FFFFFF")
+	return lipgloss.JoinVertical(lipgloss.Left, title, chart)
+}
+
+// correlation returns the Pearson correlation coefficient of two equally long
+// series; ok is false with fewer than three points or when either is constant.
+func correlation(a, b []float64) (r float64, ok bool) {
+	n := len(a)
+	if n < 3 || len(b) != n {
+		return 0, false
+	}
+	var meanA, meanB float64
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+	var cov, varA, varB float64
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	if varA == 0 || varB == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varA*varB), true
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// clipboardTools are tried in order when sysmoni runs on the local desktop.
+var clipboardTools = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"pbcopy"},
+}
+
+// termOutput is the terminal the program renders to. Each frame reaches it
+// in a single Write, so holding mu lets a raw escape sequence land between
+// frames instead of inside one. The embedded file keeps Bubble Tea's
+// terminal detection working.
+type termOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (t *termOutput) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.File.Write(b)
+}
+
+func (t *termOutput) WriteString(s string) (int, error) {
+	return t.Write([]byte(s))
+}
+
+// procSummary formats a process for pasting into a ticket.
+func procSummary(p model.Process, info model.ProcInfo, host string) string {
+	cmd := p.Command
+	if info.PID == p.PID && info.Cmdline != "" {
+		cmd = info.Cmdline
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "PID %d on %s at %s\n", p.PID, host, time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Command: %s\n", cmd)
+	if info.PID == p.PID && info.Exe != "" {
+		fmt.Fprintf(&b, "Exe:     %s\n", info.Exe)
+	}
+	fmt.Fprintf(&b, "User:    %s  Nice: %d  State: %s\n", p.User, p.Nice, stateName(p.State))
+	fmt.Fprintf(&b, "CPU:     %.1f%% (%.1f%% of machine)\n", p.CPU, p.CPUNorm)
+	fmt.Fprintf(&b, "Memory:  %.1f%% (%s change)\n", p.Memory, formatSignedBytes(p.MemDiff))
+	fmt.Fprintf(&b, "FDs:     %d (%+d)  Sockets: %d  Threads: %d\n", p.FDCount, p.FDDiff, p.Conns, p.Threads)
+	fmt.Fprintf(&b, "IO:      read %s/s (%s total), write %s/s (%s total)\n",
+		formatRate(p.ReadKBs*kib), formatBytes(p.ReadTotal), formatRate(p.WriteKBs*kib), formatBytes(p.WriteTotal))
+	fmt.Fprintf(&b, "OOM:     score %d (adj %+d)\n", p.OOMScore, p.OOMScoreAdj)
+	return b.String()
+}
+
+// copyToClipboard returns a command that writes text to out via OSC 52, which
+// reaches the local terminal even over ssh, and also hands it to a desktop
+// clipboard tool when one is installed and the session is local. It runs off
+// the UI loop (a hung xclip would freeze it) and reports the methods used for
+// what in a clipboardMsg.
+func copyToClipboard(out *termOutput, what, text string) tea.Cmd {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if os.Getenv("TMUX") != "" {
+		// tmux only forwards escape sequences wrapped in a DCS passthrough
+		seq = "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	local := os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == ""
+	return func() tea.Msg {
+		_, _ = out.WriteString(seq)
+		methods := []string{"OSC 52"}
+		if !local {
+			return clipboardMsg{what: what, how: methods[0]}
+		}
+		for _, tool := range clipboardTools {
+			if _, err := exec.LookPath(tool[0]); err != nil {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			cmd := exec.CommandContext(ctx, tool[0], tool[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			err := cmd.Run()
+			cancel()
+			if err == nil {
+				methods = append(methods, tool[0])
+				break
+			}
+		}
+		return clipboardMsg{what: what, how: strings.Join(methods, " + ")}
+	}
+}
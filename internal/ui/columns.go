@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// procColumn is one selectable column of the process table. The cmd column
+// has width 0 and takes whatever space the fixed-width columns leave.
+type procColumn struct {
+	key    string
+	header string
+	width  int
+	desc   string
+	value  func(p model.Process) string
+}
+
+// allProcColumns lists every column in display order.
+var allProcColumns = []procColumn{
+	{"cmd", "CMD", 0, "Command line", func(p model.Process) string { return p.Command }},
+	{"pid", "PID", 5, "Process ID", func(p model.Process) string {
+		if isRollup(p) {
+			return "-"
+		}
+		return formatInt(int64(p.PID))
+	}},
+	{"user", "USER", 8, "Owner", func(p model.Process) string { return truncate(p.User, 8) }},
+	{"ctr", "CONTAINER", 12, "Container name or short ID", func(p model.Process) string { return truncate(p.Container, 12) }},
+	{"state", "S", 1, "Process state (R/S/D/Z/T)", func(p model.Process) string { return p.State }},
+	{"ni", "NI", 3, "Nice value", func(p model.Process) string { return fmt.Sprintf("%d", p.Nice) }},
+	{"cpu", "CPU", 5, "CPU percent", func(p model.Process) string { return fmt.Sprintf("%.1f", p.CPU) }},
+	{"mem", "MEM", 5, "Memory percent", func(p model.Process) string { return fmt.Sprintf("%.1f", p.Memory) }},
+	{"read", "R/s", 5, "Disk read bytes/s", func(p model.Process) string {
+		if p.IODenied {
+			return "-"
+		}
+		return formatRate(p.ReadKBs * kib)
+	}},
+	{"write", "W/s", 5, "Disk write bytes/s", func(p model.Process) string {
+		if p.IODenied {
+			return "-"
+		}
+		return formatRate(p.WriteKBs * kib)
+	}},
+	{"fd", "FD", 4, "Open file descriptors", func(p model.Process) string {
+		if p.FDDenied {
+			return "-"
+		}
+		return formatInt(int64(p.FDCount))
+	}},
+	{"conn", "CN", 4, "Open sockets", func(p model.Process) string { return formatInt(int64(p.Conns)) }},
+	{"threads", "THR", 4, "Thread count", func(p model.Process) string { return formatInt(int64(p.Threads)) }},
+	{"oom", "OOM", 4, "Kernel OOM score", func(p model.Process) string { return fmt.Sprintf("%d", p.OOMScore) }},
+	{"dmem", "ΔMEM", 7, "RSS growth per sample", func(p model.Process) string { return formatSignedBytes(p.MemDiff) }},
+	{"dfd", "ΔFD", 4, "FD growth per sample", func(p model.Process) string { return fmt.Sprintf("%+d", p.FDDiff) }},
+	{"majflt", "MAJF", 5, "Major page faults/s", func(p model.Process) string { return formatInt(int64(p.MajorFaults + 0.5)) }},
+	{"blkio", "BLKIO", 5, "Block IO delay ms/s", func(p model.Process) string { return formatInt(int64(p.IOWaitMs + 0.5)) }},
+}
+
+// enabledColumns resolves configured keys to column definitions, keeping the
+// canonical display order. Unknown keys are ignored; an empty result falls
+// back to the defaults.
+func enabledColumns(keys []string) []procColumn {
+	want := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		want[strings.ToLower(k)] = true
+	}
+	var cols []procColumn
+	for _, c := range allProcColumns {
+		if want[c.key] {
+			cols = append(cols, c)
+		}
+	}
+	if len(cols) == 0 && len(keys) > 0 {
+		return enabledColumns(config.DefaultColumns)
+	}
+	return cols
+}
+
+// groupedColumns are the count columns formatInt renders, widened by a
+// separator per three digits while thousands grouping is on.
+var groupedColumns = map[string]bool{"pid": true, "fd": true, "conn": true, "threads": true, "majflt": true, "blkio": true}
+
+// procColumns is the enabled column set with headers reflecting display
+// modes: the CPU header reads CPU/N while per-process CPU is normalized.
+func (m *Model) procColumns() []procColumn {
+	cols := enabledColumns(m.cfg.Columns)
+	for i := range cols {
+		if cols[i].key == "cpu" && m.cfg.CPUNorm {
+			cols[i].header = "CPU/N"
+		}
+		if groupedColumns[cols[i].key] && thousandsSep != "" {
+			cols[i].width += (cols[i].width - 1) / 3
+		}
+	}
+	return cols
+}
+
+// fixedColumnsWidth is the space used by all non-cmd columns plus separators.
+func fixedColumnsWidth(cols []procColumn) int {
+	w := 0
+	for _, c := range cols {
+		if c.width > 0 {
+			w += c.width + 1
+		}
+	}
+	return w
+}
+
+func hasCmdColumn(cols []procColumn) bool {
+	for _, c := range cols {
+		if c.key == "cmd" {
+			return true
+		}
+	}
+	return false
+}
+
+// formatProcRow lays out a header (p == nil) or data row for the enabled columns.
+func formatProcRow(cols []procColumn, p *model.Process, cmdWidth int) string {
+	parts := make([]string, 0, len(cols))
+	for _, c := range cols {
+		val := c.header
+		if p != nil {
+			val = c.value(*p)
+		}
+		if c.width == 0 {
+			parts = append(parts, fmt.Sprintf("%-*s", cmdWidth, truncate(val, cmdWidth)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%*s", c.width, val))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatSignedBytes renders a byte delta as "+1.2M" / "-512B"; zero is "0".
+func formatSignedBytes(d int64) string {
+	switch {
+	case d > 0:
+		return "+" + formatBytes(uint64(d))
+	case d < 0:
+		return "-" + formatBytes(uint64(-d))
+	}
+	return "0"
+}
+
+// handleColumnChooserKey toggles columns; Enter/Esc/C closes and persists.
+func (m *Model) handleColumnChooserKey(key string) {
+	switch key {
+	case "up", "k":
+		if m.columnCursor > 0 {
+			m.columnCursor--
+		}
+	case "down", "j":
+		if m.columnCursor < len(allProcColumns)-1 {
+			m.columnCursor++
+		}
+	case " ", "x":
+		colKey := allProcColumns[m.columnCursor].key
+		var next []string
+		found := false
+		for _, c := range m.cfg.Columns {
+			if c == colKey {
+				found = true
+				continue
+			}
+			next = append(next, c)
+		}
+		if !found {
+			next = append(next, colKey)
+		}
+		if len(next) == 0 {
+			return // keep at least one column
+		}
+		m.cfg.Columns = next
+	case "enter", "esc", "C", "q":
+		m.showColumnChooser = false
+		if m.cfg.File == "" {
+			m.statusMsg = "Columns updated"
+			return
+		}
+		var keys []string
+		for _, c := range enabledColumns(m.cfg.Columns) {
+			keys = append(keys, c.key)
+		}
+		if err := config.SaveValue(m.cfg.File, "columns", strings.Join(keys, ",")); err != nil {
+			m.statusMsg = fmt.Sprintf("Columns updated (save failed: %v)", err)
+		} else {
+			m.statusMsg = fmt.Sprintf("Columns saved to %s", m.cfg.File)
+		}
+	}
+}
+
+// renderColumnChooser renders the column selection modal
+func (m *Model) renderColumnChooser() string {
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color(primaryColor)).
+		Padding(1, 2).
+		Width(50)
+
+	enabled := make(map[string]bool)
+	for _, c := range enabledColumns(m.cfg.Columns) {
+		enabled[c.key] = true
+	}
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(primaryColor)).Render("PROCESS TABLE COLUMNS"))
+	content.WriteString("\n\n")
+	for i, c := range allProcColumns {
+		box := "[ ]"
+		if enabled[c.key] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %-5s %s", box, c.header, c.desc)
+		style := rowStyle
+		if i == m.columnCursor {
+			style = style.Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color(primaryColor)).Bold(true)
+		} else if !enabled[c.key] {
+			style = dimStyle
+		}
+		content.WriteString(style.Render(line) + "\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(subtleStyle.Render("j/k move · space toggle · Enter/Esc save & close"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(content.String()),
+		lipgloss.WithWhitespaceChars("░"),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("#111111")))
+}
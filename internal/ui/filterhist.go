@@ -0,0 +1,54 @@
+package ui
+
+import "github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+
+// filterHistoryMax caps how many applied filters are kept across sessions.
+const filterHistoryMax = 20
+
+// loadFilterHistory reads the filters saved by earlier sessions.
+func loadFilterHistory(configFile string) []string {
+	hist, _ := config.LoadLines(config.FilterHistoryPath(configFile))
+	if len(hist) > filterHistoryMax {
+		hist = hist[len(hist)-filterHistoryMax:]
+	}
+	return hist
+}
+
+// recordFilter moves f to the end of the filter history, dropping the oldest
+// entries beyond filterHistoryMax, and saves it next to the config file.
+func (m *Model) recordFilter(f string) {
+	if f == "" {
+		return
+	}
+	hist := make([]string, 0, len(m.filterHist)+1)
+	for _, h := range m.filterHist {
+		if h != f {
+			hist = append(hist, h)
+		}
+	}
+	hist = append(hist, f)
+	if len(hist) > filterHistoryMax {
+		hist = hist[len(hist)-filterHistoryMax:]
+	}
+	m.filterHist = hist
+	_ = config.SaveLines(config.FilterHistoryPath(m.cfg.File), hist)
+}
+
+// recallFilter steps the input line through the filter history, -1 towards
+// older entries and +1 back towards the line being typed, which is kept so
+// stepping past the newest entry restores it.
+func (m *Model) recallFilter(step int) {
+	idx := m.filterHistIdx + step
+	if idx < 0 || idx > len(m.filterHist) {
+		return
+	}
+	if m.filterHistIdx == len(m.filterHist) {
+		m.filterDraft = append([]rune(nil), m.inputBuf...)
+	}
+	m.filterHistIdx = idx
+	if idx == len(m.filterHist) {
+		m.inputBuf = m.filterDraft
+	} else {
+		m.inputBuf = []rune(m.filterHist[idx])
+	}
+}
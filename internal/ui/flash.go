@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// flashStyle marks a value that just moved.
+var flashStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+
+// flashMetrics are the dashboard values that flash on a large change. A
+// change counts against the larger of floor and the two readings, so
+// percentages compare in points and rates near zero don't flicker.
+var flashMetrics = []struct {
+	name  string
+	floor float64
+	value func(model.Sample) float64
+}{
+	{"cpu", 100, func(s model.Sample) float64 { return s.CPU.Total }},
+	{"mem", 100, func(s model.Sample) float64 { return pct(s.Memory.UsedBytes, s.Memory.TotalBytes) }},
+	{"swap", 100, func(s model.Sample) float64 { return pct(s.Memory.SwapUsed, s.Memory.SwapTotal) }},
+	{"load", 1, func(s model.Sample) float64 { return s.CPU.Load1 }},
+	{"net_rx", 1, func(s model.Sample) float64 { return s.IO.NetRxMbps }},
+	{"net_tx", 1, func(s model.Sample) float64 { return s.IO.NetTxMbps }},
+	{"disk_r", 1, func(s model.Sample) float64 { return s.IO.DiskReadMBs }},
+	{"disk_w", 1, func(s model.Sample) float64 { return s.IO.DiskWriteMBs }},
+}
+
+// updateFlash compares s with the previous displayed sample and stamps the
+// metrics that moved by at least cfg.Flash percent with the current tick.
+func (m *Model) updateFlash(s model.Sample) {
+	if m.cfg.Flash <= 0 {
+		return
+	}
+	if m.flashPrev == nil {
+		m.flashPrev = make(map[string]float64, len(flashMetrics))
+		m.flashAt = make(map[string]int, len(flashMetrics))
+	}
+	for _, fm := range flashMetrics {
+		v := fm.value(s)
+		if prev, ok := m.flashPrev[fm.name]; ok {
+			scale := max(fm.floor, math.Abs(prev), math.Abs(v))
+			if math.Abs(v-prev)/scale*100 >= m.cfg.Flash {
+				m.flashAt[fm.name] = m.tickCount
+			}
+		}
+		m.flashPrev[fm.name] = v
+	}
+}
+
+// flash renders text in flashStyle while metric is lit: for cfg.FlashFor,
+// counted in UI ticks.
+func (m *Model) flash(metric, text string) string {
+	at, ok := m.flashAt[metric]
+	if !ok || m.paused {
+		return text
+	}
+	ticks := int((m.cfg.FlashFor + tickPeriod - 1) / tickPeriod)
+	if m.tickCount-at >= ticks {
+		return text
+	}
+	return flashStyle.Render(text)
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// focusLines is the height of the focus section above the process table.
+const focusLines = 3
+
+// focusState follows one process and its descendants (-pid or -track). When
+// name is set and the process exits, the next instance matching it is
+// picked up.
+type focusState struct {
+	pid        int
+	name       string // glob to re-attach by; "" = stay on pid
+	reattach   bool   // learn name from the process when only -pid was given
+	command    string
+	gone       bool
+	reattached int
+
+	procs            int // processes in the tree
+	cpu, mem         float64
+	threads, fds     int
+	readKBs, writeKB float64
+	cpuHist, memHist []float64
+}
+
+// newFocus returns the focus for cfg, or nil without -pid and -track.
+func newFocus(cfg config.Config) *focusState {
+	if cfg.FocusPID <= 0 && cfg.Track == "" {
+		return nil
+	}
+	return &focusState{pid: cfg.FocusPID, name: cfg.Track, reattach: cfg.Reattach || cfg.Track != ""}
+}
+
+func (m *Model) syncFocus() {
+	if m.sampler != nil && m.focus != nil {
+		m.sampler.SetFocus(m.focus.pid, m.focus.name)
+	}
+}
+
+// updateFocus totals the focused tree in s, re-attaching to a new instance
+// by name once the process is gone.
+func (m *Model) updateFocus(s model.Sample) {
+	f := m.focus
+	if f == nil {
+		return
+	}
+	byPID := make(map[int]model.Process, len(s.Top))
+	for _, p := range s.Top {
+		byPID[p.PID] = p
+	}
+	p, ok := byPID[f.pid]
+	if !ok && f.pid > 0 && !f.gone {
+		f.gone = true
+		if f.command == "" {
+			m.statusMsg = fmt.Sprintf("Focus: no process %d", f.pid)
+		} else {
+			m.statusMsg = fmt.Sprintf("Focus: %s (%d) exited", truncate(f.command, 20), f.pid)
+		}
+	}
+	if !ok && f.name != "" {
+		if next, found := focusRoot(s.Top, f.name); found {
+			if f.pid > 0 {
+				f.reattached++
+				m.statusMsg = fmt.Sprintf("Focus re-attached to %s (%d)", truncate(next.Command, 20), next.PID)
+			}
+			p, ok = next, true
+			f.pid, f.gone = next.PID, false
+			m.syncFocus()
+		}
+	}
+	if !ok {
+		return
+	}
+	f.command = p.Command
+	if f.reattach && f.name == "" && p.Comm != "" {
+		f.name = p.Comm
+		m.syncFocus()
+	}
+
+	f.procs, f.cpu, f.mem, f.threads, f.fds, f.readKBs, f.writeKB = 0, 0, 0, 0, 0, 0, 0
+	for _, q := range focusTree(s.Top, f.pid) {
+		f.procs++
+		f.cpu += q.CPU
+		f.mem += q.Memory
+		f.threads += q.Threads
+		f.fds += q.FDCount
+		f.readKBs += q.ReadKBs
+		f.writeKB += q.WriteKBs
+	}
+	f.cpuHist = appendHistory(f.cpuHist, f.cpu)
+	f.memHist = appendHistory(f.memHist, f.mem)
+}
+
+// focusRoot picks the oldest-looking (lowest PID) process matching name
+// whose parent does not match too, so a server's master wins over workers.
+func focusRoot(procs []model.Process, name string) (model.Process, bool) {
+	matches := make(map[int]bool)
+	for _, p := range procs {
+		if p.MatchesName(name) {
+			matches[p.PID] = true
+		}
+	}
+	var best model.Process
+	found := false
+	for _, p := range procs {
+		if matches[p.PID] && !matches[p.PPID] && (!found || p.PID < best.PID) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// focusTree returns process pid and its descendants among procs, by PID.
+func focusTree(procs []model.Process, pid int) []model.Process {
+	children := make(map[int][]model.Process)
+	var tree []model.Process
+	for _, p := range procs {
+		children[p.PPID] = append(children[p.PPID], p)
+		if p.PID == pid {
+			tree = append(tree, p)
+		}
+	}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i].PID]...)
+	}
+	sort.Slice(tree, func(i, j int) bool { return tree[i].PID < tree[j].PID })
+	return tree
+}
+
+// renderFocus renders the focus section: who is followed, the tree's totals
+// with CPU average and peak over the history window, and CPU/MEM sparklines.
+func (m *Model) renderFocus(width int) string {
+	f := m.focus
+	head := "🎯 FOCUS "
+	switch {
+	case f.pid == 0:
+		head += fmt.Sprintf("waiting for %q", f.name)
+	default:
+		head += fmt.Sprintf("%s (%s)", truncate(f.command, maxInt(8, width/3)), formatInt(int64(f.pid)))
+		if f.procs > 1 {
+			head += fmt.Sprintf(" +%d children", f.procs-1)
+		}
+		if f.gone {
+			head += " [exited]"
+		}
+		if f.reattached > 0 {
+			head += fmt.Sprintf(" ↻%d", f.reattached)
+		}
+	}
+	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(accentColor)).Bold(true)
+	if len(f.cpuHist) == 0 {
+		return strings.Join([]string{focusStyle.Render(head), subtleStyle.Render("   no samples yet"), ""}, "\n")
+	}
+
+	peak, sum := 0.0, 0.0
+	for _, v := range f.cpuHist {
+		peak, sum = max(peak, v), sum+v
+	}
+	stats := fmt.Sprintf("   CPU %.1f%% avg %.1f%% max %.1f%%  MEM %.1f%%  THR %s  FD %s  R %s/s W %s/s",
+		f.cpu, sum/float64(len(f.cpuHist)), peak, f.mem, formatInt(int64(f.threads)), formatInt(int64(f.fds)),
+		formatRate(f.readKBs*kib), formatRate(f.writeKB*kib))
+	sparkW := maxInt(8, (width-12)/2)
+	sparks := "   " + subtleStyle.Render("cpu ") + m.sparkAuto(f.cpuHist, sparkW, primaryColor) +
+		subtleStyle.Render(" mem ") + m.sparkPct(f.memHist, sparkW, "#BD93F9")
+	return strings.Join([]string{focusStyle.Render(head), subtleStyle.Render(truncate(stats, width)), sparks}, "\n")
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// hoverZone is a screen row segment whose metric gets a tooltip when the
+// mouse rests on it.
+type hoverZone struct {
+	x, y, w int
+	metric  string
+}
+
+// hoverMetrics are the dashboard gauges and sparklines with tooltips.
+var hoverMetrics = map[string]struct {
+	name   string
+	hist   func(m *Model) []float64
+	format func(v float64) string
+}{
+	"cpu":    {"CPU", func(m *Model) []float64 { return m.cpuHist }, formatPct},
+	"mem":    {"MEM", func(m *Model) []float64 { return m.memHist }, formatPct},
+	"swap":   {"SWAP", func(m *Model) []float64 { return m.swapHist }, formatPct},
+	"net_rx": {"NET RX", func(m *Model) []float64 { return m.netRxHist }, formatBitRate},
+	"net_tx": {"NET TX", func(m *Model) []float64 { return m.netTxHist }, formatBitRate},
+	"disk_r": {"DISK R", func(m *Model) []float64 { return m.diskReadHist }, formatMBs},
+	"disk_w": {"DISK W", func(m *Model) []float64 { return m.diskWriteHist }, formatMBs},
+}
+
+func formatPct(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+func formatMBs(v float64) string { return formatRate(v*mib) + "/s" }
+
+var tooltipStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color(primaryColor)).
+	Padding(0, 1)
+
+// hoverCards returns the hover zones of a row of cards joined horizontally
+// at screen row y: lines[i] names the metric on each content line of card i,
+// "" for lines without one.
+func hoverCards(y int, cards []string, lines [][]string) []hoverZone {
+	var zones []hoverZone
+	x := 0
+	for i, card := range cards {
+		w := lipgloss.Width(card)
+		for j, metric := range lines[i] {
+			if metric != "" {
+				zones = append(zones, hoverZone{x: x, y: y + 1 + j, w: w - cardStyle.GetMarginRight(), metric: metric})
+			}
+		}
+		x += w
+	}
+	return zones
+}
+
+// hoveredMetric returns the metric under the mouse, or "".
+func (m *Model) hoveredMetric() string {
+	if !m.mouseEnabled || !m.hovering || m.minimal || m.activeTab != 0 {
+		return ""
+	}
+	for _, z := range m.placed.zones {
+		if m.hoverY == z.y && m.hoverX >= z.x && m.hoverX < z.x+z.w {
+			return z.metric
+		}
+	}
+	return ""
+}
+
+// renderTooltip shows the current, min, max and average of metric's history.
+func (m *Model) renderTooltip(metric string) string {
+	hm := hoverMetrics[metric]
+	hist := hm.hist(m)
+	if len(hist) == 0 {
+		return tooltipStyle.Render(titleStyle.Render(hm.name) + "\n" + subtleStyle.Render("no data yet"))
+	}
+	lo, hi, sum := hist[0], hist[0], 0.0
+	for _, v := range hist {
+		lo, hi, sum = min(lo, v), max(hi, v), sum+v
+	}
+	span := fmt.Sprintf("%d samples", len(hist))
+	if n := len(m.timeHist); n > 1 {
+		span = "last " + m.timeHist[n-1].Sub(m.timeHist[0]).Round(1e9).String()
+	}
+	lines := []string{
+		titleStyle.Render(hm.name) + subtleStyle.Render(" "+span),
+		fmt.Sprintf("now %s", valStyle.Render(hm.format(hist[len(hist)-1]))),
+		fmt.Sprintf("min %s  max %s", hm.format(lo), hm.format(hi)),
+		fmt.Sprintf("avg %s", hm.format(sum/float64(len(hist)))),
+	}
+	return tooltipStyle.Render(strings.Join(lines, "\n"))
+}
+
+// withTooltip overlays the hovered metric's tooltip on view just below and
+// right of the mouse, flipping to the other side near the screen edges.
+func (m *Model) withTooltip(view string) string {
+	metric := m.hoveredMetric()
+	if metric == "" {
+		return view
+	}
+	tip := m.renderTooltip(metric)
+	w, h := lipgloss.Size(tip)
+	x, y := m.hoverX+2, m.hoverY+1
+	if x+w > m.width {
+		x = m.hoverX - w - 1
+	}
+	if y+h > m.height {
+		y = m.hoverY - h
+	}
+	return overlay(view, tip, maxInt(0, x), maxInt(0, y))
+}
+
+// overlay draws fg over bg with its top-left corner at cell (x, y).
+func overlay(bg, fg string, x, y int) string {
+	rows := strings.Split(bg, "\n")
+	for i, line := range strings.Split(fg, "\n") {
+		if y+i >= len(rows) {
+			break
+		}
+		row := rows[y+i]
+		left := ansi.Truncate(row, x, "")
+		left += strings.Repeat(" ", x-ansi.StringWidth(left))
+		right := ansi.TruncateLeft(row, x+ansi.StringWidth(line), "")
+		rows[y+i] = left + "\x1b[0m" + line + "\x1b[0m" + right
+	}
+	return strings.Join(rows, "\n")
+}
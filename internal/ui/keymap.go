@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultBindings lists every dashboard action with its default keys, in
+// help order. Config lines "key_<action> = <keys>" (space-separated, as
+// Bubble Tea names them: "x", "ctrl+x", "pgdown", plus "space") replace an
+// action's keys.
+var defaultBindings = []struct {
+	action string
+	keys   []string
+}{
+	{"quit", []string{"q"}},
+	{"quit_now", []string{"Q", "ctrl+c"}},
+	{"back", []string{"esc"}},
+	{"next_tab", []string{"tab"}},
+	{"prev_panel", []string{"shift+tab"}},
+	{"tab1", []string{"1"}},
+	{"tab2", []string{"2"}},
+	{"tab3", []string{"3"}},
+	{"tab4", []string{"4"}},
+	{"down", []string{"down", "j"}},
+	{"up", []string{"up", "k"}},
+	{"page_down", []string{"pgdown", "J"}},
+	{"page_up", []string{"pgup", "K"}},
+	{"home", []string{"home"}},
+	{"end", []string{"end"}},
+	{"detail", []string{"enter"}},
+	{"filter", []string{"/"}},
+	{"search", []string{":"}},
+	{"search_next", []string{";"}},
+	{"columns", []string{"C"}},
+	{"min_cpu", []string{"%"}},
+	{"min_mem", []string{"M"}},
+	{"highlight", []string{"H"}},
+	{"sort", []string{"s"}},
+	{"sort2", []string{"S"}},
+	{"reverse", []string{"r"}},
+	{"gpu", []string{"g"}},
+	{"battery", []string{"b"}},
+	{"io_panels", []string{"i"}},
+	{"side_panel", []string{"x"}},
+	{"temps", []string{"t"}},
+	{"inotify", []string{"n"}},
+	{"cgroup_view", []string{"u"}},
+	{"netstates", []string{"w"}},
+	{"cgroups", []string{"c"}},
+	{"freeze", []string{"f"}},
+	{"step", []string{"."}},
+	{"pin", []string{"p"}},
+	{"bell", []string{"a"}},
+	{"cpu_norm", []string{"z"}},
+	{"minimal", []string{"F"}},
+	{"trend", []string{"G"}},
+	{"gradient", []string{"V"}},
+	{"lifetime", []string{"L"}},
+	{"rollup", []string{"A"}},
+	{"name_mode", []string{"N"}},
+	{"kthreads", []string{"T"}},
+	{"states", []string{"Z"}},
+	{"baseline", []string{"["}},
+	{"baseline_off", []string{"]"}},
+	{"faster", []string{"+", "="}},
+	{"slower", []string{"-"}},
+	{"interval_preset", []string{"d"}},
+	{"mouse", []string{"m"}},
+	{"ionice_tip", []string{"I"}},
+	{"json", []string{"o"}},
+	{"kill_filtered", []string{"X"}},
+	{"export_csv", []string{"e"}},
+	{"screenshot", []string{"P"}},
+	{"help", []string{"?", "h"}},
+}
+
+// newKeyMap builds the key -> action lookup from defaultBindings with
+// overrides (action -> keys) applied. A key taken by an override no longer
+// triggers its default action, and ctrl+c always quits.
+func newKeyMap(overrides map[string][]string) (map[string]string, error) {
+	known := make(map[string]bool, len(defaultBindings))
+	for _, b := range defaultBindings {
+		known[b.action] = true
+	}
+	actions := make([]string, 0, len(overrides))
+	for action, keys := range overrides {
+		if !known[action] {
+			return nil, fmt.Errorf("key_%s: unknown action", action)
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("key_%s: no keys given", action)
+		}
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	keys := make(map[string]string)
+	for _, b := range defaultBindings {
+		if _, ok := overrides[b.action]; !ok {
+			for _, k := range b.keys {
+				keys[k] = b.action
+			}
+		}
+	}
+	for _, action := range actions {
+		for _, k := range overrides[action] {
+			if k == "space" {
+				k = " "
+			}
+			keys[k] = action
+		}
+	}
+	keys["ctrl+c"] = "quit_now"
+	return keys, nil
+}
+
+// CheckKeys reports the first invalid key_<action> override.
+func CheckKeys(overrides map[string][]string) error {
+	_, err := newKeyMap(overrides)
+	return err
+}
+
+// remappedKeys describes the overridden actions for the help screen, e.g.
+// "sort: x · freeze: space".
+func remappedKeys(overrides map[string][]string) string {
+	var parts []string
+	for _, b := range defaultBindings {
+		if keys, ok := overrides[b.action]; ok {
+			parts = append(parts, b.action+": "+strings.Join(keys, " "))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
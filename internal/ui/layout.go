@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// dashCard is a dashboard card the layout can place in a row.
+type dashCard struct {
+	hover  []string // metric with a tooltip on each content line, "" for none
+	render func(m *Model, s model.Sample) string
+}
+
+// dashCards are the cards by layout name. procs is the full-width process
+// table (with the right panel on wide screens); every layout has it, in a
+// row of its own.
+var dashCards = map[string]dashCard{
+	"cpu":      {[]string{"cpu", "cpu"}, (*Model).renderCPUCard},
+	"mem":      {[]string{"mem", "mem"}, (*Model).renderMemCard},
+	"swap":     {[]string{"swap", "swap"}, (*Model).renderSwapCard},
+	"net":      {[]string{"", "net_rx", "net_tx"}, (*Model).renderNetCard},
+	"disk":     {[]string{"", "disk_r", "disk_w"}, (*Model).renderDiskCard},
+	"hardware": {nil, (*Model).renderHardwareCard},
+	"procs":    {}, // sized from the other rows by layoutDashboard
+}
+
+// dashCardNames lists the cards in the built-in layout's order.
+var dashCardNames = []string{"cpu", "mem", "swap", "net", "disk", "hardware", "procs"}
+
+// defaultLayout is the dashboard without a layout setting: vitals,
+// throughput and hardware, then processes.
+var defaultLayout = [][]string{{"cpu", "mem", "swap"}, {"net", "disk", "hardware"}, {"procs"}}
+
+// CheckLayout reports an empty row, an unknown or repeated card, or procs
+// missing or sharing a row.
+func CheckLayout(rows [][]string) error {
+	if rows == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		if len(row) == 0 {
+			return fmt.Errorf("row %d is empty", i+1)
+		}
+		for _, name := range row {
+			if _, ok := dashCards[name]; !ok {
+				return fmt.Errorf("unknown card %q (want %s)", name, strings.Join(dashCardNames, ", "))
+			}
+			if seen[name] {
+				return fmt.Errorf("card %q appears twice", name)
+			}
+			seen[name] = true
+			if name == "procs" && len(row) > 1 {
+				return fmt.Errorf("procs needs a row to itself")
+			}
+		}
+	}
+	if !seen["procs"] {
+		return fmt.Errorf("no procs row; the process table can move but not go")
+	}
+	return nil
+}
+
+func (m *Model) layout() [][]string {
+	if m.cfg.Layout != nil {
+		return m.cfg.Layout
+	}
+	return defaultLayout
+}
+
+// procsHeight is the process card's height as last laid out, or before the
+// first layout what the built-in rows leave (about 22 lines), between 6 and 20.
+func (m *Model) procsHeight() int {
+	if m.placed.procsHeight > 0 {
+		return m.placed.procsHeight
+	}
+	return maxInt(6, minInt(20, m.height-22))
+}
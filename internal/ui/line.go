@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// lineField is one {placeholder} of the -line template: its text and how
+// alarming it is (0 fine, 1 warning, 2 critical) for color-coding.
+type lineField struct {
+	text  func(s model.Sample) string
+	level func(s model.Sample) int
+}
+
+// levelOf grades v against a warning and a critical threshold.
+func levelOf(v, warn, crit float64) int {
+	switch {
+	case v >= crit:
+		return 2
+	case v >= warn:
+		return 1
+	}
+	return 0
+}
+
+func hottest(s model.Sample) float64 {
+	t := 0.0
+	for _, z := range s.Temps {
+		t = max(t, z.Temp)
+	}
+	return t
+}
+
+func memPct(s model.Sample) float64  { return pct(s.Memory.UsedBytes, s.Memory.TotalBytes) }
+func swapPct(s model.Sample) float64 { return pct(s.Memory.SwapUsed, s.Memory.SwapTotal) }
+func loadPerCore(s model.Sample) float64 {
+	return s.CPU.Load1 / float64(maxInt(1, len(s.CPU.PerCore))) * 100
+}
+
+func never(model.Sample) int { return 0 }
+
+// lineFields are the -line placeholders; thresholds match the dashboard's.
+var lineFields = map[string]lineField{
+	"cpu": {func(s model.Sample) string { return fmt.Sprintf("%.0f%%", s.CPU.Total) },
+		func(s model.Sample) int { return levelOf(s.CPU.Total, 70, 90) }},
+	"mem": {func(s model.Sample) string { return fmt.Sprintf("%.0f%%", memPct(s)) },
+		func(s model.Sample) int { return levelOf(memPct(s), 70, 90) }},
+	"swap": {func(s model.Sample) string { return fmt.Sprintf("%.0f%%", swapPct(s)) },
+		func(s model.Sample) int { return levelOf(swapPct(s), 50, 80) }},
+	"load": {func(s model.Sample) string { return fmt.Sprintf("%.2f", s.CPU.Load1) },
+		func(s model.Sample) int { return levelOf(loadPerCore(s), 80, 100) }},
+	"rx":    {func(s model.Sample) string { return formatBitRate(s.IO.NetRxMbps) }, never},
+	"tx":    {func(s model.Sample) string { return formatBitRate(s.IO.NetTxMbps) }, never},
+	"read":  {func(s model.Sample) string { return formatRate(s.IO.DiskReadMBs*mib) + "/s" }, never},
+	"write": {func(s model.Sample) string { return formatRate(s.IO.DiskWriteMBs*mib) + "/s" }, never},
+	"temp": {func(s model.Sample) string {
+		if len(s.Temps) == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f°C", hottest(s))
+	}, func(s model.Sample) int { return levelOf(hottest(s), 70, 85) }},
+}
+
+// lineColors wrap warning and critical fields for each -line-color mode:
+// warning start, critical start, reset.
+var lineColors = map[string][3]string{
+	"none":    {"", "", ""},
+	"ansi":    {"\x1b[33m", "\x1b[31;1m", "\x1b[0m"},
+	"tmux":    {"#[fg=yellow]", "#[fg=red,bold]", "#[default]"},
+	"polybar": {"%{F#FFAA00}", "%{F#FF4444}", "%{F-}"},
+}
+
+var linePlaceholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+// CheckLine reports an unknown placeholder in format or an unknown color mode.
+func CheckLine(format, color string) error {
+	if _, ok := lineColors[color]; !ok {
+		return fmt.Errorf("unknown color mode %q (want none, ansi, tmux or polybar)", color)
+	}
+	for _, p := range linePlaceholder.FindAllString(format, -1) {
+		if _, ok := lineFields[strings.Trim(p, "{}")]; !ok {
+			return fmt.Errorf("unknown placeholder %s", p)
+		}
+	}
+	return nil
+}
+
+// WriteLine prints s through the -line template, e.g. "CPU 34% MEM 61%
+// NET ↓2.1Mb/s ↑400kb/s T 58°C", coloring fields past their warning or
+// critical thresholds in the configured color mode.
+func WriteLine(w io.Writer, cfg config.Config, s model.Sample) error {
+	setNumberFormat(cfg)
+	colors := lineColors[cfg.LineColor] // validated in main
+	line := linePlaceholder.ReplaceAllStringFunc(cfg.LineFormat, func(p string) string {
+		f := lineFields[strings.Trim(p, "{}")]
+		text := f.text(s)
+		if level := f.level(s); level > 0 && colors[2] != "" {
+			return colors[level-1] + text + colors[2]
+		}
+		return text
+	})
+	_, err := io.WriteString(w, line+"\n")
+	return err
+}
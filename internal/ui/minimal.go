@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// minimalPanels are the panels minimal mode maximizes, indexed by focusedPanel.
+// IO and FD stand for the side panel slots, which x can switch.
+var minimalPanels = []string{"Procs", "Vitals", "IO", "FD", "Throttled"}
+
+// minimalPanelBar is the header tab strip naming the focused panel.
+func (m *Model) minimalPanelBar(active, inactive lipgloss.Style) string {
+	var tabs []string
+	for i, name := range minimalPanels {
+		if i == 2 || i == 3 {
+			name = m.sidePanel(i - 2).tab
+		}
+		if i == m.focusedPanel {
+			tabs = append(tabs, active.Render(name))
+		} else {
+			tabs = append(tabs, inactive.Render(name))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Bottom, tabs...)
+}
+
+// minimalRows is the process table height (header row included) in minimal
+// mode: everything but the header, panel title and footer lines.
+func (m *Model) minimalRows() int {
+	return maxInt(2, m.height-3-m.pinnedLines())
+}
+
+// renderMinimal renders the focused panel alone at full terminal size,
+// without cards, for small terminals, tmux splits and serial consoles.
+func (m *Model) renderMinimal(s model.Sample) string {
+	width := maxInt(20, m.width-1)
+	height := maxInt(2, m.height-3)
+	var title, body string
+	switch m.focusedPanel {
+	case 1:
+		title = "VITALS"
+		body = m.renderMinimalVitals(s, width)
+	case 2, 3:
+		sp := m.sidePanel(m.focusedPanel - 2)
+		title = sp.title
+		body = sp.render(m.sideTop(sp, s.Top), height, width)
+	case 4:
+		throttled := m.sortAndFilter(s.Throttled)
+		title = fmt.Sprintf("🔻 THROTTLED (%d)", len(throttled))
+		body = renderProcessTableCompact(throttled, height, secondaryColor)
+	default:
+		procs := m.topRows(s)
+		cols, _ := m.topLayout()
+		title = fmt.Sprintf("TOP PROCESSES (%d)", len(procs))
+		body = m.withPinned(renderProcessColumns(procs, cols, m.minimalRows(), width, m.topOffset, m.procTableOpts()), width)
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(title), strings.TrimRight(body, "\n"))
+	return lipgloss.NewStyle().Height(m.height - 2).MaxHeight(m.height - 2).Render(content)
+}
+
+// renderMinimalVitals stacks CPU/MEM/SWAP/LOAD gauges and IO totals, one per line.
+func (m *Model) renderMinimalVitals(s model.Sample, width int) string {
+	spark := func(gauge string, hist []float64, color string) string {
+		w := width - lipgloss.Width(gauge) - 2
+		if w < 4 {
+			return gauge
+		}
+		return lipgloss.JoinHorizontal(lipgloss.Bottom, gauge, "  ", m.sparkPct(hist, minInt(w, 60), color))
+	}
+	cores := float64(maxInt(1, len(s.CPU.PerCore)))
+	lines := []string{
+		spark(renderGauge("CPU", s.CPU.Total), m.cpuHist, primaryColor),
+		renderCPUWaits(s.CPU),
+		spark(renderGaugeEnhanced("MEM", pct(s.Memory.UsedBytes, s.Memory.TotalBytes), "#BD93F9", true), m.memHist, "#BD93F9"),
+		renderGaugeEnhanced("SWAP", pct(s.Memory.SwapUsed, s.Memory.SwapTotal), warningColor, true),
+		renderGauge("LOAD/CORE", s.CPU.Load1/cores*100) +
+			subtleStyle.Render(fmt.Sprintf(" %.2f %.2f %.2f", s.CPU.Load1, s.CPU.Load5, s.CPU.Load15)),
+		renderTasks(s.Tasks, len(s.CPU.PerCore)),
+		fmt.Sprintf("NET  ↓%8s ↑%8s", formatBitRate(s.IO.NetRxMbps), formatBitRate(s.IO.NetTxMbps)),
+		fmt.Sprintf("DISK R%5s/s W%5s/s", formatRate(s.IO.DiskReadMBs*mib), formatRate(s.IO.DiskWriteMBs*mib)),
+	}
+	if len(s.Temps) > 0 {
+		hot := s.Temps[0]
+		for _, t := range s.Temps {
+			if t.Temp > hot.Temp {
+				hot = t
+			}
+		}
+		lines = append(lines, fmt.Sprintf("TEMP %.0f°C (%s)", hot.Temp, truncate(hot.Zone, 16)))
+	}
+	return strings.Join(lines, "\n")
+}
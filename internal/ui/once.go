@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// onceCmdWidth caps the CMD column in -once output unless cmd_width is set.
+const onceCmdWidth = 60
+
+// WriteOnce prints s for -once, like top -bn1: a few lines of vitals, then
+// the process table with the configured columns, sort keys, thresholds and
+// -filter (a case-insensitive substring of the command, as / matches).
+func WriteOnce(w io.Writer, cfg config.Config, s model.Sample) error {
+	setNumberFormat(cfg)
+	m := &Model{cfg: cfg, sortKey: cfg.Sort, sortKey2: cfg.Sort2, nameMode: cfg.NameMode, filter: cfg.Filter}
+	loc, _ := cfg.Location() // validated in main
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "sysmoni %s  load %.2f %.2f %.2f  (%d cores)\n",
+		s.Timestamp.In(loc).Format("15:04:05"), s.CPU.Load1, s.CPU.Load5, s.CPU.Load15, len(s.CPU.PerCore))
+	fmt.Fprintf(&b, "CPU   %5.1f%%  iowait %.1f%%  steal %.1f%%\n", s.CPU.Total, s.CPU.IOWait, s.CPU.Steal)
+	fmt.Fprintf(&b, "Mem   %5.1f%%  %s / %s  swap %s / %s\n", pct(s.Memory.UsedBytes, s.Memory.TotalBytes),
+		formatBytes(s.Memory.UsedBytes), formatBytes(s.Memory.TotalBytes),
+		formatBytes(s.Memory.SwapUsed), formatBytes(s.Memory.SwapTotal))
+	fmt.Fprintf(&b, "Net   ↓%s ↑%s  disk R %s/s W %s/s\n", formatBitRate(s.IO.NetRxMbps), formatBitRate(s.IO.NetTxMbps),
+		formatRate(s.IO.DiskReadMBs*mib), formatRate(s.IO.DiskWriteMBs*mib))
+	if t := s.Tasks; t.Processes > 0 {
+		fmt.Fprintf(&b, "Tasks %s procs, %s thr, %s run, %s D\n",
+			formatInt(int64(t.Processes)), formatInt(int64(t.Threads)), formatInt(int64(t.Running)), formatInt(int64(t.Blocked)))
+	}
+	if len(s.Stuck) > 0 {
+		fmt.Fprintf(&b, "Stuck %s\n", stuckNames(s.Stuck, 8))
+	}
+
+	rows := m.filterMatches(s.Top)
+	cols := m.procColumns()
+	cmdWidth := 3
+	for _, p := range rows {
+		cmdWidth = max(cmdWidth, len([]rune(p.Command)))
+	}
+	if limit := cfg.CmdWidth; limit > 0 {
+		cmdWidth = min(cmdWidth, limit)
+	} else {
+		cmdWidth = min(cmdWidth, onceCmdWidth)
+	}
+	sortDesc := "sorted by " + m.sortKey
+	if cfg.Filter != "" {
+		sortDesc += fmt.Sprintf(", %d matching %q", len(rows), cfg.Filter)
+	}
+	fmt.Fprintf(&b, "\n%s\n", sortDesc)
+	b.WriteString(strings.TrimRight(formatProcRow(cols, nil, cmdWidth), " ") + "\n")
+	for i := range rows {
+		b.WriteString(strings.TrimRight(formatProcRow(cols, &rows[i], cmdWidth), " ") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// pinTombstone is how long an exited pinned process stays listed.
+const pinTombstone = 5 * time.Second
+
+// togglePin pins or unpins the selected process (the top row when nothing
+// is selected).
+func (m *Model) togglePin() {
+	procs := m.topRows(m.latest)
+	idx := maxInt(m.selectedProc, 0)
+	if idx >= len(procs) {
+		m.statusMsg = "No process to pin"
+		return
+	}
+	p := procs[idx]
+	if isRollup(p) {
+		m.statusMsg = "Expand the group (Enter) to pin one of its processes"
+		return
+	}
+	for i, pid := range m.pins {
+		if pid == p.PID {
+			m.pins = append(m.pins[:i], m.pins[i+1:]...)
+			delete(m.pinLast, pid)
+			delete(m.pinGoneAt, pid)
+			m.syncPins()
+			m.statusMsg = fmt.Sprintf("Unpinned %d", pid)
+			return
+		}
+	}
+	m.pins = append(m.pins, p.PID)
+	m.pinLast[p.PID] = p
+	m.syncPins()
+	m.statusMsg = fmt.Sprintf("Pinned %s (%d)", truncate(p.Command, 20), p.PID)
+}
+
+func (m *Model) syncPins() {
+	if m.sampler != nil {
+		m.sampler.SetPinned(m.pins)
+	}
+}
+
+// updatePins refreshes pinned rows from a new sample and drops tombstones
+// older than pinTombstone.
+func (m *Model) updatePins(s model.Sample) {
+	if len(m.pins) == 0 {
+		return
+	}
+	byPID := make(map[int]model.Process, len(s.Top))
+	for _, p := range s.Top {
+		byPID[p.PID] = p
+	}
+	kept := m.pins[:0]
+	for _, pid := range m.pins {
+		if p, ok := byPID[pid]; ok {
+			m.pinLast[pid] = p
+			delete(m.pinGoneAt, pid)
+			kept = append(kept, pid)
+			continue
+		}
+		gone, ok := m.pinGoneAt[pid]
+		if !ok {
+			m.pinGoneAt[pid] = s.Timestamp
+			kept = append(kept, pid)
+		} else if s.Timestamp.Sub(gone) < pinTombstone {
+			kept = append(kept, pid)
+		} else {
+			delete(m.pinLast, pid)
+			delete(m.pinGoneAt, pid)
+		}
+	}
+	if len(kept) != len(m.pins) {
+		m.pins = kept
+		m.syncPins()
+	} else {
+		m.pins = kept
+	}
+}
+
+// pinnedLines is the height of the sticky focus and pinned sections (0 when
+// both are empty).
+func (m *Model) pinnedLines() int {
+	n := 0
+	if m.focus != nil {
+		n = focusLines
+	}
+	if len(m.pins) == 0 {
+		return n
+	}
+	return n + len(m.pins) + 1
+}
+
+// renderPinned renders the sticky pinned section shown above the table.
+func (m *Model) renderPinned(width int) string {
+	if len(m.pins) == 0 {
+		return ""
+	}
+	cols := m.procColumns()
+	cmdWidth := maxInt(8, width-fixedColumnsWidth(cols)-4) // "📌 " prefix
+	pinStyle := rowStyle.Foreground(lipgloss.Color(warningColor))
+	var b strings.Builder
+	b.WriteString(subtleStyle.Render(fmt.Sprintf("📌 PINNED (%d, p to unpin)", len(m.pins))))
+	for _, pid := range m.pins {
+		p := m.pinLast[pid]
+		b.WriteString("\n")
+		if _, gone := m.pinGoneAt[pid]; gone {
+			tag := "[exited]"
+			if m.remote != nil {
+				tag = "[not reported]"
+			}
+			b.WriteString(dimStyle.Render(fmt.Sprintf("   %-*s %s %s", cmdWidth, truncate(p.Command, cmdWidth), formatInt(int64(pid)), tag)))
+			continue
+		}
+		b.WriteString(pinStyle.Render("📌 " + formatProcRow(cols, &p, cmdWidth)))
+	}
+	return b.String()
+}
+
+// withPinned stacks the focus and pinned sections on top of the process table.
+func (m *Model) withPinned(table string, width int) string {
+	if len(m.pins) > 0 {
+		table = lipgloss.JoinVertical(lipgloss.Left, m.renderPinned(width), table)
+	}
+	if m.focus != nil {
+		table = lipgloss.JoinVertical(lipgloss.Left, m.renderFocus(width), table)
+	}
+	return table
+}
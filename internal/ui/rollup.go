@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// Rollup rows are synthesized processes with PID 0 (never a real /proc
+// entry) whose Comm holds the group key; signalProcs already skips PID <= 1.
+
+// topRows is the process table's row list: sortAndFilter, then collapsed by
+// command when rollup is on.
+func (m *Model) topRows(s model.Sample) []model.Process {
+	rows := m.sortAndFilter(s.Top)
+	if m.rollup {
+		rows = m.rollupRows(rows)
+	}
+	return rows
+}
+
+// isRollup reports whether p is a synthesized group row.
+func isRollup(p model.Process) bool { return p.PID == 0 }
+
+// rollupKey groups processes by executable name: comm when sampled, else
+// the basename of the first command word.
+func rollupKey(p model.Process) string {
+	if p.Comm != "" {
+		return p.Comm
+	}
+	if f := strings.Fields(p.Command); len(f) > 0 {
+		return filepath.Base(f[0])
+	}
+	return p.Command
+}
+
+// rollupRows collapses sorted rows sharing a rollupKey into one summed row
+// per group, re-sorts the groups, and lists members under expanded ones.
+// Commands that run once stay as plain rows.
+func (m *Model) rollupRows(rows []model.Process) []model.Process {
+	var order []string
+	members := make(map[string][]model.Process)
+	for _, p := range rows {
+		k := rollupKey(p)
+		if _, ok := members[k]; !ok {
+			order = append(order, k)
+		}
+		members[k] = append(members[k], p)
+	}
+	heads := make([]model.Process, 0, len(order))
+	for _, k := range order {
+		if ms := members[k]; len(ms) == 1 {
+			heads = append(heads, ms[0])
+		} else {
+			heads = append(heads, sumGroup(k, ms))
+		}
+	}
+	heads = m.sortProcs(heads)
+
+	out := make([]model.Process, 0, len(rows))
+	for _, h := range heads {
+		out = append(out, h)
+		if !isRollup(h) || !m.rollupOpen[h.Comm] {
+			continue
+		}
+		for _, p := range members[h.Comm] {
+			p.Command = "└ " + p.Command
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// sumGroup adds up a group's metrics; the OOM score is the worst member's.
+func sumGroup(key string, ms []model.Process) model.Process {
+	g := model.Process{Comm: key, Nice: ms[0].Nice, User: ms[0].User}
+	for _, p := range ms {
+		g.CPU += p.CPU
+		g.CPUNorm += p.CPUNorm
+		g.Memory += p.Memory
+		g.ReadKBs += p.ReadKBs
+		g.WriteKBs += p.WriteKBs
+		g.FDCount += p.FDCount
+		g.FDDiff += p.FDDiff
+		g.MemDiff += p.MemDiff
+		g.Conns += p.Conns
+		g.Threads += p.Threads
+		g.ReadTotal += p.ReadTotal
+		g.WriteTotal += p.WriteTotal
+		g.CPUTimeSeconds += p.CPUTimeSeconds
+		g.MinorFaults += p.MinorFaults
+		g.MajorFaults += p.MajorFaults
+		g.IOWaitMs += p.IOWaitMs
+		g.OOMScore = max(g.OOMScore, p.OOMScore)
+		if p.User != g.User {
+			g.User = "*"
+		}
+	}
+	g.Command = fmt.Sprintf("%s (×%d)", key, len(ms))
+	return g
+}
+
+// toggleRollupGroup expands or collapses the group row at idx; it reports
+// false when that row is a plain process.
+func (m *Model) toggleRollupGroup(rows []model.Process, idx int) bool {
+	if idx < 0 || idx >= len(rows) || !isRollup(rows[idx]) {
+		return false
+	}
+	key := rows[idx].Comm
+	m.rollupOpen[key] = !m.rollupOpen[key]
+	if !m.rollupOpen[key] {
+		delete(m.rollupOpen, key)
+	}
+	m.clampTopOffset()
+	return true
+}
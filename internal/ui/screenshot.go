@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SVG cell metrics for the screen export, sized for a 14px monospace font.
+const (
+	svgCellWidth  = 8.4
+	svgLineHeight = 17
+	svgFontSize   = 14
+	svgDefaultFg  = "#DDDDDD"
+)
+
+// exportScreen writes the current view to sysmoni-screen-<time>.txt with the
+// styling stripped and to a matching .svg with colors kept, both in the
+// working directory, and returns the two paths.
+func (m *Model) exportScreen() (txtPath, svgPath string, err error) {
+	view := m.View()
+	base := fmt.Sprintf("sysmoni-screen-%s", time.Now().Format("20060102-150405"))
+	txtPath, svgPath = base+".txt", base+".svg"
+	if err := os.WriteFile(txtPath, []byte(screenText(view)), 0644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(svgPath, []byte(screenSVG(view)), 0644); err != nil {
+		return "", "", err
+	}
+	return txtPath, svgPath, nil
+}
+
+// screenText strips escape sequences and trailing blanks from a rendered view.
+func screenText(view string) string {
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		var b strings.Builder
+		for _, r := range parseANSI(line) {
+			b.WriteString(r.text)
+		}
+		lines[i] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// screenSVG renders a view as an SVG terminal snapshot. Each styled run is
+// placed at its cell column and stretched to its cell width, so wide glyphs
+// (emoji, braille) can't push the rest of the line out of alignment.
+func screenSVG(view string) string {
+	lines := strings.Split(strings.TrimRight(view, "\n"), "\n")
+	cols := 0
+	for _, line := range lines {
+		cols = maxInt(cols, lipgloss.Width(line))
+	}
+	width := float64(cols) * svgCellWidth
+	height := len(lines) * svgLineHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%d" font-family="monospace" font-size="%d">`+"\n",
+		width, height, svgFontSize)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", bgDimColor)
+	for y, line := range lines {
+		col := 0
+		for _, r := range parseANSI(line) {
+			w := lipgloss.Width(r.text)
+			if w == 0 {
+				continue
+			}
+			x := float64(col) * svgCellWidth
+			col += w
+			if r.bg != "" {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+					x, y*svgLineHeight, float64(w)*svgCellWidth, svgLineHeight, r.bg)
+			}
+			if strings.TrimSpace(r.text) == "" {
+				continue
+			}
+			fg := r.fg
+			if fg == "" {
+				fg = svgDefaultFg
+			}
+			attrs := fmt.Sprintf(`fill="%s"`, fg)
+			if r.bold {
+				attrs += ` font-weight="bold"`
+			}
+			if r.italic {
+				attrs += ` font-style="italic"`
+			}
+			if r.underline {
+				attrs += ` text-decoration="underline"`
+			}
+			fmt.Fprintf(&b, `<text x="%.1f" y="%d" textLength="%.1f" lengthAdjust="spacingAndGlyphs" xml:space="preserve" %s>%s</text>`+"\n",
+				x, (y+1)*svgLineHeight-4, float64(w)*svgCellWidth, attrs, html.EscapeString(r.text))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// styledRun is a stretch of one rendered line in a single SGR style; colors
+// are "#RRGGBB", or "" for the terminal default.
+type styledRun struct {
+	text                    string
+	fg, bg                  string
+	bold, italic, underline bool
+}
+
+// parseANSI splits a rendered line into styled runs. SGR sequences set the
+// style; other CSI and OSC sequences are dropped.
+func parseANSI(line string) []styledRun {
+	var runs []styledRun
+	var cur styledRun
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			cur.text = text.String()
+			runs = append(runs, cur)
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(line); i++ {
+		if line[i] != 0x1b {
+			text.WriteByte(line[i])
+			continue
+		}
+		if i+1 >= len(line) {
+			break
+		}
+		switch line[i+1] {
+		case '[':
+			j := i + 2
+			for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+				j++
+			}
+			if j < len(line) && line[j] == 'm' {
+				flush()
+				cur = applySGR(cur, line[i+2:j])
+			}
+			i = j
+		case ']':
+			// OSC runs to BEL or ST (ESC \)
+			j := i + 2
+			for j < len(line) && line[j] != '\a' && !(line[j] == 0x1b && j+1 < len(line) && line[j+1] == '\\') {
+				j++
+			}
+			if j < len(line) && line[j] == 0x1b {
+				j++
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	flush()
+	return runs
+}
+
+// applySGR returns st updated by the ";"-separated SGR parameters.
+func applySGR(st styledRun, params string) styledRun {
+	ps := strings.Split(params, ";")
+	num := func(i int) int {
+		if i >= len(ps) {
+			return 0
+		}
+		n, _ := strconv.Atoi(ps[i])
+		return n
+	}
+	for i := 0; i < len(ps); i++ {
+		switch n := num(i); {
+		case n == 0:
+			st = styledRun{}
+		case n == 1:
+			st.bold = true
+		case n == 3:
+			st.italic = true
+		case n == 4:
+			st.underline = true
+		case n == 22:
+			st.bold = false
+		case n == 23:
+			st.italic = false
+		case n == 24:
+			st.underline = false
+		case n >= 30 && n <= 37:
+			st.fg = ansiColor(n - 30)
+		case n >= 90 && n <= 97:
+			st.fg = ansiColor(n - 90 + 8)
+		case n == 39:
+			st.fg = ""
+		case n >= 40 && n <= 47:
+			st.bg = ansiColor(n - 40)
+		case n >= 100 && n <= 107:
+			st.bg = ansiColor(n - 100 + 8)
+		case n == 49:
+			st.bg = ""
+		case n == 38 || n == 48:
+			var c string
+			switch num(i + 1) {
+			case 5:
+				c = ansiColor(num(i + 2))
+				i += 2
+			case 2:
+				c = fmt.Sprintf("#%02X%02X%02X", num(i+2), num(i+3), num(i+4))
+				i += 4
+			}
+			if n == 38 {
+				st.fg = c
+			} else {
+				st.bg = c
+			}
+		}
+	}
+	return st
+}
+
+// ansi16 is the xterm palette for the 16 basic colors.
+var ansi16 = [16]string{
+	"#000000", "#CD0000", "#00CD00", "#CDCD00", "#0000EE", "#CD00CD", "#00CDCD", "#E5E5E5",
+	"#7F7F7F", "#FF0000", "#00FF00", "#FFFF00", "#5C5CFF", "#FF00FF", "#00FFFF", "#FFFFFF",
+}
+
+// ansiColor maps an xterm 256-color index to "#RRGGBB".
+func ansiColor(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return ansi16[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02X%02X%02X", levels[n/36], levels[n/6%6], levels[n%6])
+	}
+	g := 8 + 10*(n-232)
+	return fmt.Sprintf("#%02X%02X%02X", g, g, g)
+}
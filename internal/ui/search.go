@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startSearch opens the : prompt. Unlike / it hides nothing: each keystroke
+// moves the selection to the first matching row at or after where it was,
+// and Esc puts the selection and scroll position back.
+func (m *Model) startSearch() {
+	m.searchMode = true
+	m.searchBuf = nil
+	m.searchFromSel = m.selectedProc
+	m.searchFromOff = m.topOffset
+}
+
+// searchKey handles a key while the : prompt is open.
+func (m *Model) searchKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searchMode = false
+		if pattern := strings.TrimSpace(string(m.searchBuf)); pattern != "" {
+			m.search = pattern
+		}
+		m.searchBuf = nil
+		return
+	case tea.KeyEsc:
+		m.searchMode = false
+		m.searchBuf = nil
+		m.selectedProc, m.topOffset = m.searchFromSel, m.searchFromOff
+		m.statusMsg = ""
+		return
+	case tea.KeyBackspace:
+		if len(m.searchBuf) > 0 {
+			m.searchBuf = m.searchBuf[:len(m.searchBuf)-1]
+		}
+	default:
+		if msg.Runes == nil {
+			return
+		}
+		m.searchBuf = append(m.searchBuf, msg.Runes...)
+	}
+	pattern := strings.TrimSpace(string(m.searchBuf))
+	if pattern == "" {
+		m.selectedProc, m.topOffset = m.searchFromSel, m.searchFromOff
+		return
+	}
+	if idx := m.findRow(pattern, max(m.searchFromSel, 0)); idx >= 0 {
+		m.selectRow(idx)
+		m.statusMsg = ""
+	} else {
+		m.statusMsg = fmt.Sprintf("No match for %q", pattern)
+	}
+}
+
+// nextSearchMatch moves the selection to the next row matching the last
+// search, wrapping past the end of the table.
+func (m *Model) nextSearchMatch() {
+	if m.search == "" {
+		m.statusMsg = "No search yet (: to start one)"
+		return
+	}
+	idx := m.findRow(m.search, m.selectedProc+1)
+	switch {
+	case idx < 0:
+		m.statusMsg = fmt.Sprintf("No match for %q", m.search)
+		return
+	case idx <= m.selectedProc:
+		m.statusMsg = "Search wrapped to the top"
+	default:
+		m.statusMsg = ""
+	}
+	m.selectRow(idx)
+}
+
+// findRow returns the index of the first table row at or after start whose
+// command contains pattern (case-insensitive), wrapping around, or -1.
+func (m *Model) findRow(pattern string, start int) int {
+	rows := m.topRows(m.latest)
+	pattern = strings.ToLower(pattern)
+	for i := range rows {
+		idx := (start + i) % len(rows)
+		if strings.Contains(strings.ToLower(rows[idx].Command), pattern) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// selectRow selects table row idx and scrolls just enough to show it.
+func (m *Model) selectRow(idx int) {
+	m.selectedProc = idx
+	if visible := m.visibleTopCapacity(); idx >= m.topOffset+visible {
+		m.bumpTopOffset(idx - (m.topOffset + visible) + 1)
+	} else if idx < m.topOffset {
+		m.bumpTopOffset(idx - m.topOffset)
+	}
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// sideLeaders is how many processes a side panel lists.
+const sideLeaders = 8
+
+// sidePanel is a leader list the dashboard's right panel (and minimal mode's
+// IO/FD slots) can show, ranked by a sortValue key.
+type sidePanel struct {
+	key    string // sortValue key, also the -side-panels name
+	tab    string // minimal mode tab label
+	title  string
+	render func(procs []model.Process, height, width int) string
+}
+
+// sidePanels are the choices, in the order x cycles through them.
+var sidePanels = []sidePanel{
+	{"io", "IO", "⚡ IO TOP", renderIOTable},
+	{"fd", "FD", "📂 FD TOP", renderFDTable},
+	{"threads", "Threads", "🧵 THREADS TOP", leaderTable(func(p model.Process) string { return formatInt(int64(p.Threads)) })},
+	{"conn", "Conns", "🔌 CONN TOP", leaderTable(func(p model.Process) string { return formatInt(int64(p.Conns)) })},
+	{"dmem", "MemΔ", "📈 MEM GROWTH TOP", leaderTable(func(p model.Process) string { return "+" + formatBytes(uint64(max(p.MemDiff, 0))) })},
+	{"majflt", "Faults", "💾 MAJFLT TOP", leaderTable(func(p model.Process) string { return fmt.Sprintf("%.0f/s", p.MajorFaults) })},
+	{"blkio", "BlkIO", "⏳ BLKIO TOP", leaderTable(func(p model.Process) string { return fmt.Sprintf("%.0fms/s", p.IOWaitMs) })},
+}
+
+// sidePanelIndex returns the sidePanels index for key, or -1.
+func sidePanelIndex(key string) int {
+	for i, sp := range sidePanels {
+		if sp.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckSidePanels reports an unknown -side-panels name, the same panel
+// twice or more than two.
+func CheckSidePanels(keys []string) error {
+	if len(keys) > 2 {
+		return fmt.Errorf("at most two side panels, got %d", len(keys))
+	}
+	if len(keys) == 2 && keys[0] == keys[1] {
+		return fmt.Errorf("side panel %q given twice", keys[0])
+	}
+	for _, k := range keys {
+		if sidePanelIndex(k) < 0 {
+			names := make([]string, len(sidePanels))
+			for i, sp := range sidePanels {
+				names[i] = sp.key
+			}
+			return fmt.Errorf("unknown side panel %q (want %s)", k, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// sidePanel returns the panel in slot 0 (upper) or 1 (lower).
+func (m *Model) sidePanel(slot int) sidePanel { return sidePanels[m.sideSlots[slot]] }
+
+// cycleSidePanel moves slot to the next panel not already in the other slot.
+func (m *Model) cycleSidePanel(slot int) {
+	i := m.sideSlots[slot]
+	for {
+		i = (i + 1) % len(sidePanels)
+		if i != m.sideSlots[1-slot] {
+			break
+		}
+	}
+	m.sideSlots[slot] = i
+	m.statusMsg = fmt.Sprintf("Side panel: %s", sidePanels[i].title)
+}
+
+// sideTop returns the leaders for panel sp.
+func (m *Model) sideTop(sp sidePanel, procs []model.Process) []model.Process {
+	sorted := append([]model.Process{}, procs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sortValue(sorted[i], sp.key) > sortValue(sorted[j], sp.key)
+	})
+	if len(sorted) > sideLeaders {
+		sorted = sorted[:sideLeaders]
+	}
+	return sorted
+}
+
+// leaderTable renders a side panel as CMD and one right-aligned value.
+func leaderTable(value func(p model.Process) string) func([]model.Process, int, int) string {
+	return func(procs []model.Process, height, width int) string {
+		var b strings.Builder
+		cmdWidth := maxInt(8, width-12)
+		for i, p := range procs {
+			if i >= height {
+				break
+			}
+			style := rowStyle
+			if i%2 == 0 {
+				style = dimStyle
+			}
+			b.WriteString(style.Render(fmt.Sprintf("%-*s %10s", cmdWidth, truncate(p.Command, cmdWidth), value(p))) + "\n")
+		}
+		return b.String()
+	}
+}
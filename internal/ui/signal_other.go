@@ -0,0 +1,12 @@
+//go:build !unix
+
+package ui
+
+import (
+	"errors"
+	"syscall"
+)
+
+func sendSignal(pid int, sig syscall.Signal) error {
+	return errors.New("signals are not supported on this platform")
+}
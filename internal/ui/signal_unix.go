@@ -0,0 +1,7 @@
+//go:build unix
+
+package ui
+
+import "syscall"
+
+func sendSignal(pid int, sig syscall.Signal) error { return syscall.Kill(pid, sig) }
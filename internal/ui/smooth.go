@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// smoother applies an exponentially weighted moving average to the rate
+// metrics (network, disk, per-device and per-process IO) before display.
+// The raw sample is left untouched for JSON output.
+type smoother struct {
+	alpha  float64 // weight of the newest sample; 0 or 1 disables smoothing
+	primed bool
+	io     model.IO
+	dev    map[string]model.IODevice
+	proc   map[int]procRates
+}
+
+type procRates struct {
+	read  float64
+	write float64
+}
+
+func newSmoother(alpha float64) *smoother {
+	return &smoother{alpha: alpha, dev: make(map[string]model.IODevice), proc: make(map[int]procRates)}
+}
+
+func (sm *smoother) enabled() bool { return sm != nil && sm.alpha > 0 && sm.alpha < 1 }
+
+// ewma blends cur into prev; the first reading of a series passes through.
+func (sm *smoother) ewma(prev, cur float64, seen bool) float64 {
+	if !seen {
+		return cur
+	}
+	return sm.alpha*cur + (1-sm.alpha)*prev
+}
+
+// apply returns a copy of s with smoothed rates. Series that disappear from a
+// sample (exited processes, removed devices) are forgotten.
+func (sm *smoother) apply(s model.Sample) model.Sample {
+	if !sm.enabled() {
+		return s
+	}
+	sm.io.NetRxMbps = sm.ewma(sm.io.NetRxMbps, s.IO.NetRxMbps, sm.primed)
+	sm.io.NetTxMbps = sm.ewma(sm.io.NetTxMbps, s.IO.NetTxMbps, sm.primed)
+	sm.io.DiskReadMBs = sm.ewma(sm.io.DiskReadMBs, s.IO.DiskReadMBs, sm.primed)
+	sm.io.DiskWriteMBs = sm.ewma(sm.io.DiskWriteMBs, s.IO.DiskWriteMBs, sm.primed)
+	sm.primed = true
+	s.IO.NetRxMbps, s.IO.NetTxMbps = sm.io.NetRxMbps, sm.io.NetTxMbps
+	s.IO.DiskReadMBs, s.IO.DiskWriteMBs = sm.io.DiskReadMBs, sm.io.DiskWriteMBs
+
+	devs := make([]model.IODevice, len(s.IO.PerDevice))
+	dev := make(map[string]model.IODevice, len(devs))
+	for i, d := range s.IO.PerDevice {
+		prev, seen := sm.dev[d.Name]
+		d.ReadMBs = sm.ewma(prev.ReadMBs, d.ReadMBs, seen)
+		d.WriteMBs = sm.ewma(prev.WriteMBs, d.WriteMBs, seen)
+		devs[i], dev[d.Name] = d, d
+	}
+	s.IO.PerDevice, sm.dev = devs, dev
+
+	next := make(map[int]procRates, len(s.Top))
+	s.Top = sm.procs(s.Top, next)
+	s.Throttled = sm.procs(s.Throttled, next)
+	sm.proc = next
+	return s
+}
+
+// procs smooths per-process IO rates into next; a PID already blended this
+// sample (Top and Throttled overlap) reuses that result.
+func (sm *smoother) procs(rows []model.Process, next map[int]procRates) []model.Process {
+	out := make([]model.Process, len(rows))
+	for i, p := range rows {
+		r, done := next[p.PID]
+		if !done {
+			prev, seen := sm.proc[p.PID]
+			r = procRates{read: sm.ewma(prev.read, p.ReadKBs, seen), write: sm.ewma(prev.write, p.WriteKBs, seen)}
+			next[p.PID] = r
+		}
+		p.ReadKBs, p.WriteKBs = r.read, r.write
+		out[i] = p
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// recordDeviceHistory appends this sample's per-interface and per-device
+// rates to their history buffers, keyed by name like perCoreHist is by core.
+// Interfaces and devices missing from the sample lose their history, so
+// short-lived veths don't pile up.
+func (m *Model) recordDeviceHistory(s model.Sample) {
+	nics := make(map[string]bool, len(s.IO.PerNIC))
+	for _, n := range s.IO.PerNIC {
+		nics[n.Name] = true
+		m.nicRxHist[n.Name] = appendHistory(m.nicRxHist[n.Name], n.RxMbps)
+		m.nicTxHist[n.Name] = appendHistory(m.nicTxHist[n.Name], n.TxMbps)
+	}
+	devs := make(map[string]bool, len(s.IO.PerDevice))
+	for _, d := range s.IO.PerDevice {
+		devs[d.Name] = true
+		m.devReadHist[d.Name] = appendHistory(m.devReadHist[d.Name], d.ReadMBs)
+		m.devWriteHist[d.Name] = appendHistory(m.devWriteHist[d.Name], d.WriteMBs)
+	}
+	for _, h := range []map[string][]float64{m.nicRxHist, m.nicTxHist} {
+		for name := range h {
+			if !nics[name] {
+				delete(h, name)
+			}
+		}
+	}
+	for _, h := range []map[string][]float64{m.devReadHist, m.devWriteHist} {
+		for name := range h {
+			if !devs[name] {
+				delete(h, name)
+			}
+		}
+	}
+}
+
+// appendHistory appends v to hist, keeping the newest historyPoints values.
+func appendHistory(hist []float64, v float64) []float64 {
+	hist = append(hist, v)
+	if len(hist) > historyPoints {
+		hist = hist[len(hist)-historyPoints:]
+	}
+	return hist
+}
+
+// renderThroughput is the Throughput tab: one row per network interface and
+// per block device, each with its current rates and their sparklines.
+func (m *Model) renderThroughput(s model.Sample) string {
+	availHeight := m.height - 4
+	leftWidth := m.width / 2
+	rightWidth := m.width - leftWidth - 2
+
+	nics := append([]model.NetIface(nil), s.IO.PerNIC...)
+	sort.Slice(nics, func(i, j int) bool { return nics[i].Name < nics[j].Name })
+	devs := append([]model.IODevice(nil), s.IO.PerDevice...)
+	sort.Slice(devs, func(i, j int) bool { return devs[i].Name < devs[j].Name })
+
+	nicRows := make([]throughputRow, len(nics))
+	for i, n := range nics {
+		nicRows[i] = throughputRow{n.Name,
+			"↓" + formatBitRate(n.RxMbps), m.nicRxHist[n.Name],
+			"↑" + formatBitRate(n.TxMbps), m.nicTxHist[n.Name]}
+	}
+	devRows := make([]throughputRow, len(devs))
+	for i, d := range devs {
+		devRows[i] = throughputRow{d.Name,
+			"R" + formatRate(d.ReadMBs*mib) + "/s", m.devReadHist[d.Name],
+			"W" + formatRate(d.WriteMBs*mib) + "/s", m.devWriteHist[d.Name]}
+	}
+
+	nicCard := renderThroughputCard(m, "🌐 NETWORK INTERFACES", nicRows, successColor, "#0077FF",
+		"no interfaces (or all excluded by --net-exclude)", leftWidth, availHeight)
+	devCard := renderThroughputCard(m, "💽 DISK DEVICES", devRows, warningColor, secondaryColor,
+		"no block devices (or all excluded by --disk-exclude)", rightWidth, availHeight)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(leftWidth).Render(nicCard),
+		lipgloss.NewStyle().Width(rightWidth).Render(devCard))
+}
+
+// throughputRow is one entity on the Throughput tab: two labelled rates with
+// their histories.
+type throughputRow struct {
+	name   string
+	label1 string
+	hist1  []float64
+	label2 string
+	hist2  []float64
+}
+
+// renderThroughputCard lays rows out as "name  rate1 spark1  rate2 spark2",
+// splitting what width is left after the labels between the two sparklines.
+func renderThroughputCard(m *Model, title string, rows []throughputRow, color1, color2, empty string, width, height int) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(title) + "\n")
+	if len(rows) == 0 {
+		b.WriteString(subtleStyle.Render(empty))
+		return cardStyle.Width(width - 2).Height(height).Render(b.String())
+	}
+	nameW := 6
+	for _, r := range rows {
+		nameW = maxInt(nameW, minInt(lipgloss.Width(r.name), 16))
+	}
+	const rateW = 10
+	sparkW := maxInt(5, (width-6-nameW-2*(rateW+2))/2)
+	maxRows := maxInt(1, height-2)
+	for i, r := range rows {
+		if i == maxRows {
+			b.WriteString("\n" + subtleStyle.Render(fmt.Sprintf("… %d more", len(rows)-i)))
+			break
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%-*s %*s %s %*s %s", nameW, truncate(r.name, nameW),
+			rateW, r.label1, m.sparkAuto(r.hist1, sparkW, color1),
+			rateW, r.label2, m.sparkAuto(r.hist2, sparkW, color2))
+	}
+	return cardStyle.Width(width - 2).Height(height).Render(b.String())
+}
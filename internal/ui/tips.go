@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/sampler"
+)
+
+// tipNice is the nice value the renice tip sets. renice takes it as an
+// absolute value, so the tip is skipped for processes already this nice.
+const tipNice = 10
+
+// priorityTips are the detail modal's runnable versions of the ionice/renice
+// tips, keyed by the modal key that previews and then runs them.
+var priorityTips = map[string]struct {
+	tool string
+	args func(pid int) []string
+}{
+	"i": {"ionice", func(pid int) []string { return []string{"-c3", "-p", fmt.Sprint(pid)} }},
+	"n": {"renice", func(pid int) []string { return []string{fmt.Sprintf("+%d", tipNice), "-p", fmt.Sprint(pid)} }},
+}
+
+// tipCheck is the result of checking whether a tip can run.
+type tipCheck struct {
+	argv []string
+	path string // resolved tool path; "" when not installed
+	why  string // reason the tip would fail; "" when it should work
+	who  string // how permission was established
+}
+
+func (c tipCheck) command() string { return strings.Join(c.argv, " ") }
+
+// checkTip resolves the tool for key and predicts whether it may act on p:
+// root may do anything; other users may only lower the priority of processes
+// they own.
+func checkTip(key string, p model.Process) tipCheck {
+	tip := priorityTips[key]
+	c := tipCheck{argv: append([]string{tip.tool}, tip.args(p.PID)...)}
+	if path, err := exec.LookPath(tip.tool); err == nil {
+		c.path = path
+	} else {
+		c.why = tip.tool + " is not installed"
+		return c
+	}
+	euid := os.Geteuid()
+	if euid == 0 {
+		c.who = "running as root"
+		return c
+	}
+	ruid, puid, err := sampler.UIDs(p.PID)
+	switch {
+	case err != nil:
+		c.why = fmt.Sprintf("cannot read the owner of PID %d: %v", p.PID, err)
+	case ruid != euid && puid != euid:
+		c.why = fmt.Sprintf("PID %d belongs to %s; needs root", p.PID, p.User)
+	default:
+		c.who = "own process"
+	}
+	return c
+}
+
+// runPriorityTip previews the tip on the first press of key and runs it on
+// the second; any other modal key or process change disarms it.
+func (m *Model) runPriorityTip(key string) {
+	var proc model.Process
+	found := false
+	for _, p := range m.latest.Top {
+		if p.PID == m.detailPID {
+			proc, found = p, true
+			break
+		}
+	}
+	m.detailErr = true
+	if !found {
+		m.detailNote = "Process not in the current sample"
+		return
+	}
+	if m.remote != nil {
+		m.detailNote = "Priority tips only run against local processes"
+		return
+	}
+	if m.guard.Protects(proc) {
+		m.tipArmed = ""
+		m.detailNote = fmt.Sprintf("PID %d (%s) is protected; not running %s (protect in config)",
+			proc.PID, truncate(proc.Command, 20), priorityTips[key].tool)
+		return
+	}
+	if key == "n" && proc.Nice >= tipNice {
+		m.tipArmed = ""
+		m.detailNote = fmt.Sprintf("PID %d already runs at nice %d; renice would not lower it", proc.PID, proc.Nice)
+		return
+	}
+	c := checkTip(key, proc)
+	if m.tipArmed != key {
+		if c.path != "" {
+			m.tipArmed = key
+		}
+		if c.why != "" {
+			m.detailNote = fmt.Sprintf("Dry run: %s would fail: %s", c.command(), c.why)
+			return
+		}
+		m.detailErr = false
+		m.detailNote = fmt.Sprintf("Dry run: %s (%s, %s) · %s again to run", c.command(), c.path, c.who, key)
+		if key == "n" {
+			m.detailNote = fmt.Sprintf("Dry run: %s sets nice to %d (%s, %s) · %s again to run", c.command(), tipNice, c.path, c.who, key)
+		}
+		return
+	}
+	m.tipArmed = ""
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, c.argv[0], c.argv[1:]...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		m.detailNote = fmt.Sprintf("✗ %s failed: %s", c.command(), msg)
+	} else {
+		m.detailErr = false
+		m.detailNote = "✓ Ran " + c.command()
+	}
+	m.statusMsg = m.detailNote
+}
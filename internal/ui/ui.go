@@ -4,21 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/alert"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/config"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/export"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/remote"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/retention"
 	"github.com/Dicklesworthstone/system_resource_protection_script/internal/sampler"
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/watchdog"
 )
 
 const (
 	historyPoints  = 60
+	killSafetyCap  = 50 // bulk kills above this need a second confirmation
+	minInterval    = 250 * time.Millisecond
+	maxInterval    = 10 * time.Second
 	primaryColor   = "#00D7FF" // Cyan
 	secondaryColor = "#FF005F" // Pink/Red
 	successColor   = "#00FF87" // Green
@@ -29,6 +42,7 @@ const (
 	coolColor      = "#00BFFF" // Deep sky blue for cool temps
 	warmColor      = "#FFA500" // Orange for warm temps
 	hotColor       = "#FF4500" // OrangeRed for hot temps
+	zombieColor    = "#BD93F9" // Purple for zombie process rows
 	accentColor    = "#9D4EDD" // Purple accent
 	bgDimColor     = "#1a1a1a" // Subtle background
 )
@@ -116,76 +130,245 @@ var (
 type Model struct {
 	cfg       config.Config
 	latest    model.Sample
+	sampler   *sampler.Sampler // nil in remote mode
+	remote    *remote.Client
 	stream    <-chan model.Sample
+	out       *termOutput // the program's output; raw sequences go through it
 	ctxCancel context.CancelFunc
 	width     int
 	height    int
 	topOffset int
 
-	sortKey   string
-	filter    string
-	inputMode bool
-	inputBuf  []rune
+	sortKey       string
+	sortKey2      string // tiebreak; "" picks mem for cpu, else cpu
+	sortAsc       bool
+	nameMode      string // CMD display: cmdline|comm|exe
+	filter        string
+	inputMode     bool
+	inputBuf      []rune
+	highlightMode bool // keep all rows and highlight matches instead of filtering
+
+	// Applied filters, oldest first, recalled with up/down in / input mode;
+	// filterHistIdx == len(filterHist) is the line being typed (filterDraft)
+	filterHist    []string
+	filterHistIdx int
+	filterDraft   []rune
+
+	// : search selects matching rows without hiding the rest; searchFrom*
+	// is where the selection was when the prompt opened, restored on Esc,
+	// and search is the last confirmed pattern, repeated with ;
+	searchMode    bool
+	searchBuf     []rune
+	search        string
+	searchFromSel int
+	searchFromOff int
 
 	// History for sparklines
+	timeHist      []time.Time
 	cpuHist       []float64
 	memHist       []float64
+	swapHist      []float64
 	netRxHist     []float64
 	netTxHist     []float64
 	diskReadHist  []float64
 	diskWriteHist []float64
 
 	perCoreHist map[int][]float64
+	gpuUtilHist map[int][]float64 // by GPU index, percent
+	gpuMemHist  map[int][]float64 // by GPU index, VRAM used percent
+
+	// Throughput tab history, by interface / block device name
+	nicRxHist    map[string][]float64
+	nicTxHist    map[string][]float64
+	devReadHist  map[string][]float64
+	devWriteHist map[string][]float64
+
+	// Full raw samples over the retention window, for longer-range statistics
+	retained *retention.Ring
+
+	redactor *sampler.Redactor // nil unless --redact; masks the detail view's cmdline
+
+	fatalErr error // why the TUI quit on its own; RunTUI returns it
 
 	// Statistics (Session)
 	cumulativeCPU map[string]float64
+	statsAt       time.Time             // timestamp of the last sample counted
+	lifetimeCPU   map[int]lifetimeEntry // by PID, until the process exits
+	lifetimeGone  map[string]float64    // totals of exited or reused PIDs, by command
+	shameLifetime bool                  // Hall of Shame ranks lifetime CPU time
+	trendMetric   int                   // index into trendMetrics for the Analysis chart
 	throttleCount map[string]int
-	activeTab     int // 0=Dashboard, 1=Analysis, 2=System Info
+	activeTab     int // 0=Dashboard, 1=Analysis, 2=System Info, 3=Throughput
 	showHelp      bool
 	paused        bool
 	showIOPanels  bool
+	sideSlots     [2]int // sidePanels shown in the right panel's upper and lower slots
 	showGPU       bool
 	showBatt      bool
 	showTemps     bool
 	showInotify   bool
 	showCgroups   bool
+	cgroupView    int // index into cgroupViews for the cgroup panel
 	statusMsg     string
+	keys          map[string]string // key -> action, see keymap.go
 
 	// Mouse support
 	mouseEnabled bool
 	selectedProc int // index of selected process (-1 = none)
-	focusedPanel int // 0=procs, 1=io, 2=fd, 3=throttled
+	focusedPanel int // minimal mode panel: 0=procs, 1=vitals, 2=io, 3=fd, 4=throttled
+
+	// Minimal mode shows only the focused panel, without cards
+	minimal bool
+
+	// Sparkline bars colored by value (green→red) instead of per metric
+	sparkGradient bool
+
+	// Dashboard split: right panel share of the width (0 = automatic), and
+	// whether its border is being dragged
+	splitRatio float64
+	dragSplit  bool
+
+	// Hover tooltips: the last mouse position. placed is where the
+	// dashboard's cards sit, updated on resize, keys and new samples.
+	hovering       bool
+	hoverX, hoverY int
+	placed         dashPlacement
+
+	// Rollup collapses processes by command; rollupOpen lists expanded groups
+	rollup     bool
+	rollupOpen map[string]bool
+
+	// Freeze-and-diff baseline (nil map = diff mode off)
+	baselineAt    time.Time
+	baselineByPID map[int]model.Process
+
+	// Pinned processes stay visible above the table
+	pins      []int       // PIDs in pin order
+	focus     *focusState // -pid/-track focus mode; nil when off
+	pinLast   map[int]model.Process
+	pinGoneAt map[int]time.Time // first sample a pinned PID was missing
+
+	// Column chooser modal
+	showColumnChooser bool
+	columnCursor      int
 
 	// Process detail modal
 	showProcDetail bool
 	detailPID      int
+	detailInfo     model.ProcInfo // fetched when the modal opens or moves
+	detailShowEnv  bool
+	detailNote     string // last y/i/n result, cleared when the modal moves
+	detailErr      bool   // detailNote reports a failure
+	tipArmed       string // priority tip key previewed and awaiting a second press
+
+	// Bulk kill confirmation (0=none, 1=confirm, 2=extra confirm over cap);
+	// killSpared are the matches left out because guard protects them
+	killStage   int
+	killTargets []model.Process
+	killSpared  []model.Process
+
+	// Processes kill, the priority tips and the watchdog refuse to touch
+	guard *watchdog.Guard
 
 	// Alert tracking
-	alertCount   int
-	criticalCPU  bool
-	criticalMem  bool
-	criticalSwap bool
-	criticalTemp bool
+	alertHook     *alert.Hook
+	alertCount    int
+	criticalCPU   bool
+	criticalMem   bool
+	criticalSwap  bool
+	criticalTemp  bool
+	criticalFD    bool // system-wide file handle table nearly full
+	criticalStuck bool // StuckAlert or more processes in D sleep: a hung mount or failing disk
+	bellMuted     bool
 
 	// Animation state
 	tickCount int
 
+	// Sample gaps: the sampler's ticker drops ticks while the UI isn't reading
+	droppedSamples int
+	lastDropAt     time.Time
+	skipGapCheck   bool // set on resume so the pause itself isn't counted
+
+	// Change flash: last value and the tick it last moved, per flashMetrics
+	flashPrev map[string]float64
+	flashAt   map[string]int
+
+	// Measured time between samples (EWMA), 0 until two have arrived
+	measuredInterval time.Duration
+
+	// Step mode: while frozen, "." pulls one sample taken after stepAt
+	stepPending bool
+	stepAt      time.Time
+
+	smooth *smoother // EWMA over displayed rates (-smooth)
+
+	loc *time.Location // zone the clock and timestamps are shown in (-tz)
+
+	// Watchdog (-watchdog/-enforce); nil when off or watching a remote host
+	watchdog    *watchdog.Watchdog
+	watchdogLog []watchdog.Action // newest last, capped at watchdogLogSize
+
 	jsonFile string
+	jsonOut  *export.RotatingFile // kept open while JSON output is on; closed by teardown
+	jsonProj *export.Projector
+
+	confirmingQuit bool
+
+	// accessHinted is set once the "run as root" hint has been shown
+	accessHinted bool
 }
 
 func New(cfg config.Config) *Model {
 	ctx, cancel := context.WithCancel(context.Background())
-	s := sampler.New(cfg.Interval)
-	return &Model{
+	var (
+		s        *sampler.Sampler
+		rc       *remote.Client
+		stream   <-chan model.Sample
+		redactor *sampler.Redactor
+	)
+	if cfg.Remote != "" {
+		rc = remote.New(cfg.Remote, cfg.RemoteCmd, remoteArgs(cfg))
+		stream = rc.Stream(ctx)
+		if cfg.Redact {
+			redactor = sampler.NewRedactor(cfg.RedactKeys)
+		}
+	} else {
+		s = sampler.FromConfig(cfg)
+		redactor = s.Redact
+		cfg.Interval = s.Interval
+		stream = s.Stream(ctx)
+	}
+	setNumberFormat(cfg)
+	m := &Model{
 		cfg:           cfg,
-		stream:        s.Stream(ctx),
+		sampler:       s,
+		remote:        rc,
+		stream:        stream,
+		out:           &termOutput{File: os.Stdout},
 		ctxCancel:     cancel,
 		width:         120,
 		height:        40,
-		sortKey:       "cpu",
+		sortKey:       cfg.Sort,
+		sortKey2:      cfg.Sort2,
+		nameMode:      cfg.NameMode,
+		smooth:        newSmoother(cfg.Smooth),
+		retained:      retention.New(cfg.RetentionMax, cfg.Retention),
+		redactor:      redactor,
 		filter:        "",
 		perCoreHist:   make(map[int][]float64),
+		gpuUtilHist:   make(map[int][]float64),
+		gpuMemHist:    make(map[int][]float64),
+		nicRxHist:     make(map[string][]float64),
+		nicTxHist:     make(map[string][]float64),
+		devReadHist:   make(map[string][]float64),
+		devWriteHist:  make(map[string][]float64),
+		pinLast:       make(map[int]model.Process),
+		pinGoneAt:     make(map[int]time.Time),
 		cumulativeCPU: make(map[string]float64),
+		lifetimeCPU:   make(map[int]lifetimeEntry),
+		filterHist:    loadFilterHistory(cfg.File),
+		lifetimeGone:  make(map[string]float64),
+		shameLifetime: cfg.LifetimeCPU,
 		throttleCount: make(map[string]int),
 		showIOPanels:  true,
 		showGPU:       cfg.EnableGPU,
@@ -195,21 +378,210 @@ func New(cfg config.Config) *Model {
 		showCgroups:   false,
 		mouseEnabled:  true,
 		selectedProc:  -1,
+		alertHook:     alert.New(cfg.AlertCmd, cfg.AlertWebhook, cfg.AlertDebounce),
 		focusedPanel:  0,
+		minimal:       cfg.Minimal,
+		sparkGradient: cfg.SparkGradient,
+		splitRatio:    cfg.SplitRatio,
+		rollupOpen:    make(map[string]bool),
+		guard:         watchdog.NewGuard(cfg.Protect),
+		focus:         newFocus(cfg),
 		jsonFile: func() string {
 			return os.Getenv("SRPS_SYSMONI_JSON_FILE")
 		}(),
+		jsonProj: func() *export.Projector {
+			p, _ := export.NewProjector(cfg.JSONFields) // validated in main
+			return p
+		}(),
+		activeTab: tabIndex(cfg.Tab),
+		keys: func() map[string]string {
+			k, _ := newKeyMap(cfg.Keys) // validated in main
+			return k
+		}(),
+	}
+	m.sideSlots = [2]int{0, 1}
+	for i, k := range cfg.SidePanels {
+		m.sideSlots[i] = sidePanelIndex(k) // validated in main
+	}
+	if len(cfg.SidePanels) == 1 && m.sideSlots[0] == m.sideSlots[1] {
+		m.sideSlots[1] = 0 // the first panel other than the given one
+		if m.sideSlots[0] == 0 {
+			m.sideSlots[1] = 1
+		}
+	}
+	if cfg.Panels != nil {
+		m.setPanels(cfg.Panels)
+		if s != nil {
+			s.SetGPU(m.showGPU)
+			s.SetBattery(m.showBatt)
+		}
+	}
+	if m.loc, _ = cfg.Location(); m.loc == nil { // validated in main
+		m.loc = time.Local
+	}
+	m.alertHook.Quiet, _ = alert.ParseQuietHours(cfg.QuietHours, m.loc) // validated in main
+	m.alertHook.QuietAlways = cfg.QuietAlways
+	if cfg.Watchdog {
+		if rc != nil {
+			m.statusMsg = "Watchdog only runs against local processes; ignored for remote hosts"
+		} else {
+			m.watchdog = watchdog.New(cfg.WatchdogCPU, cfg.WatchdogSamples, cfg.WatchdogNice, cfg.WatchdogIONice, cfg.Enforce)
+			m.watchdog.Exempt = watchdog.NewGuard(append(append([]string{}, cfg.Protect...), cfg.WatchdogExempt...))
+		}
+	}
+	return m
+}
+
+// clock formats t as a time of day in the configured zone.
+func (m *Model) clock(t time.Time) string { return t.In(m.loc).Format("15:04:05") }
+
+// headerClock shows when the sample was captured (not the wall clock, so a
+// frozen or remote view stays truthful), with the date and zone when asked
+// for, followed by the sample interval and, in parentheses, the measured time
+// between samples.
+func (m *Model) headerClock(s model.Sample) string {
+	layout := "15:04:05"
+	if m.cfg.ShowDate {
+		layout = "2006-01-02 " + layout
+	}
+	if m.cfg.ShowDate || m.cfg.TZ != "" {
+		layout += " MST"
+	}
+	if s.Interval <= 0 {
+		return s.Timestamp.In(m.loc).Format(layout)
+	}
+	clock := s.Timestamp.In(m.loc).Format(layout) + " · " + s.Interval.String()
+	if m.measuredInterval > 0 && !m.paused {
+		clock += " (" + m.measuredInterval.Round(10*time.Millisecond).String() + ")"
+	}
+	return clock
+}
+
+// tabNames are the -tab values, in tab order.
+var tabNames = []string{"dashboard", "analysis", "system", "throughput"}
+
+// tabIndex resolves a -tab name (or 1-4); unknown values open the Dashboard.
+func tabIndex(name string) int {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, t := range tabNames {
+		if name == t || name == fmt.Sprint(i+1) {
+			return i
+		}
+	}
+	return 0
+}
+
+// panelToggle ties a -panels name to one of the Model's visibility flags.
+type panelToggle struct {
+	name string
+	on   *bool
+}
+
+func (m *Model) panelToggles() []panelToggle {
+	return []panelToggle{
+		{"io", &m.showIOPanels},
+		{"gpu", &m.showGPU},
+		{"battery", &m.showBatt},
+		{"temps", &m.showTemps},
+		{"inotify", &m.showInotify},
+		{"cgroups", &m.showCgroups},
+	}
+}
+
+// setPanels shows exactly the listed panels.
+func (m *Model) setPanels(names []string) {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[strings.ToLower(n)] = true
+	}
+	for _, t := range m.panelToggles() {
+		*t.on = want[t.name]
+	}
+}
+
+// CheckTab reports a -tab value that names no tab.
+func CheckTab(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+	for i, t := range tabNames {
+		if name == t || name == fmt.Sprint(i+1) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown tab %q (want %s or 1-%d)", name, strings.Join(tabNames, ", "), len(tabNames))
+}
+
+// CheckPanels reports an unknown -panels name; "none" hides them all.
+func CheckPanels(names []string) error {
+	choices := []string{"none"}
+	for _, t := range (&Model{}).panelToggles() {
+		choices = append(choices, t.name)
+	}
+	for _, n := range names {
+		if err := checkChoice(strings.ToLower(n), choices); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveView records the current view for -remember-view.
+func (m *Model) saveView() {
+	if !m.cfg.RememberView {
+		return
 	}
+	var panels []string
+	for _, t := range m.panelToggles() {
+		if *t.on {
+			panels = append(panels, t.name)
+		}
+	}
+	if len(panels) == 0 {
+		panels = []string{"none"} // an empty list would mean the defaults
+	}
+	_ = config.SaveView(m.cfg.File, map[string]string{
+		"tab":    tabNames[m.activeTab],
+		"sort":   m.sortKey,
+		"sort2":  m.sortKey2,
+		"name":   m.nameMode,
+		"panels": strings.Join(panels, ","),
+	})
 }
 
 // Messages
 type tickMsg struct{}
 
-func tickCmd() tea.Cmd { return tea.Tick(time.Second/5, func(time.Time) tea.Msg { return tickMsg{} }) }
+// clipboardMsg reports a finished copy: what was copied and how.
+type clipboardMsg struct{ what, how string }
 
-func (m *Model) Init() tea.Cmd { return tickCmd() }
+// tickPeriod is how often the UI polls the sample stream and animates.
+const tickPeriod = time.Second / 5
+
+func tickCmd() tea.Cmd { return tea.Tick(tickPeriod, func(time.Time) tea.Msg { return tickMsg{} }) }
+
+func (m *Model) Init() tea.Cmd { return tea.Batch(tickCmd(), mouseMode(m.mouseEnabled)) }
+
+// mouseMode reports every mouse move, for hover tooltips, only while mouse
+// support is on; otherwise just clicks, drags and the wheel.
+func mouseMode(on bool) tea.Cmd {
+	if on {
+		return tea.EnableMouseAllMotion
+	}
+	return tea.EnableMouseCellMotion
+}
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	switch msg.(type) {
+	case tea.WindowSizeMsg, tea.KeyMsg:
+		m.placeDashboard()
+	}
+	return next, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
@@ -218,13 +590,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.mouseEnabled {
 			switch msg.Action {
 			case tea.MouseActionPress:
+				if msg.Button == tea.MouseButtonLeft && m.onSplitBorder(msg.X, msg.Y) {
+					m.dragSplit = true
+					break
+				}
 				// Handle click on process list area (rough hit testing)
-				if msg.Y >= 15 && msg.Y < m.height-2 {
+				top, bottom := m.placed.procsTop+1+m.pinnedLines(), m.placed.procsBottom-1 // border, pinned
+				if m.minimal {
+					top, bottom = 2+m.pinnedLines(), m.height-2 // header, panel title
+				}
+				if msg.Y >= top && msg.Y < bottom && (!m.minimal || m.focusedPanel == 0) {
 					// Clicked in process area - calculate which process
-					clickedRow := msg.Y - 16
+					clickedRow := msg.Y - top - 1
 					if clickedRow >= 0 {
 						newSel := m.topOffset + clickedRow
-						procs := m.sortAndFilter(m.latest.Top)
+						procs := m.topRows(m.latest)
 						if newSel < len(procs) {
 							m.selectedProc = newSel
 							m.statusMsg = fmt.Sprintf("Selected: %s (PID %d)", truncate(procs[newSel].Command, 20), procs[newSel].PID)
@@ -232,7 +612,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			case tea.MouseActionMotion:
-				// Could add hover effects here
+				if m.dragSplit {
+					m.setSplitFromX(msg.X)
+					break
+				}
+				m.hovering, m.hoverX, m.hoverY = true, msg.X, msg.Y
+			case tea.MouseActionRelease:
+				if m.dragSplit {
+					m.dragSplit = false
+					m.saveSplit()
+				}
 			}
 			// Scroll wheel
 			if msg.Button == tea.MouseButtonWheelUp {
@@ -242,11 +631,40 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case tea.KeyMsg:
+		if m.confirmingQuit {
+			m.confirmingQuit = false
+			if k := msg.String(); k == "y" || m.keys[k] == "quit" || m.keys[k] == "quit_now" {
+				return m, m.quit()
+			}
+			m.statusMsg = "Quit cancelled"
+			return m, nil
+		}
+		if m.killStage > 0 {
+			m.handleKillConfirm(msg.String())
+			return m, nil
+		}
+		if m.showColumnChooser {
+			m.handleColumnChooserKey(msg.String())
+			return m, nil
+		}
 		// Close modal first if open
 		if m.showProcDetail {
-			if msg.String() == "esc" || msg.String() == "enter" || msg.String() == "q" {
+			if k := msg.String(); k != m.tipArmed {
+				m.tipArmed = ""
+			}
+			switch msg.String() {
+			case "esc", "enter", "q":
 				m.showProcDetail = false
-				return m, nil
+			case "down", "j":
+				m.stepDetail(1)
+			case "up", "k":
+				m.stepDetail(-1)
+			case "v":
+				m.detailShowEnv = !m.detailShowEnv
+			case "y":
+				return m, m.copyDetail()
+			case "i", "n":
+				m.runPriorityTip(msg.String())
 			}
 			return m, nil
 		}
@@ -254,32 +672,61 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.Type {
 			case tea.KeyEnter:
 				m.filter = strings.TrimSpace(string(m.inputBuf))
+				m.recordFilter(m.filter)
 				m.inputMode = false
 				m.inputBuf = nil
 				m.topOffset = 0
 				m.selectedProc = -1 // Reset selection when filter changes
+				if m.highlightMode {
+					m.scrollToFirstMatch()
+				}
 				return m, nil
 			case tea.KeyEsc:
 				m.inputMode = false
 				m.inputBuf = nil
 				return m, nil
+			case tea.KeyUp, tea.KeyDown:
+				if msg.Type == tea.KeyUp {
+					m.recallFilter(-1)
+				} else {
+					m.recallFilter(1)
+				}
+				if m.highlightMode {
+					m.scrollToFirstMatch()
+				}
+				return m, nil
 			case tea.KeyBackspace:
 				if len(m.inputBuf) > 0 {
 					m.inputBuf = m.inputBuf[:len(m.inputBuf)-1]
 				}
+				if m.highlightMode {
+					m.scrollToFirstMatch()
+				}
 				return m, nil
 			default:
 				if msg.Runes != nil {
 					m.inputBuf = append(m.inputBuf, msg.Runes...)
 				}
+				if m.highlightMode {
+					m.scrollToFirstMatch()
+				}
 				return m, nil
 			}
 		}
-		switch msg.String() {
-		case "q", "ctrl+c":
-			m.ctxCancel()
-			return m, tea.Quit
-		case "esc":
+		if m.searchMode {
+			m.searchKey(msg)
+			return m, nil
+		}
+		switch m.keys[msg.String()] {
+		case "quit":
+			if m.cfg.ConfirmQuit {
+				m.confirmingQuit = true
+				return m, nil
+			}
+			return m, m.quit()
+		case "quit_now":
+			return m, m.quit()
+		case "back":
 			if m.filter != "" {
 				m.filter = ""
 				m.topOffset = 0
@@ -287,85 +734,301 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.selectedProc >= 0 {
 				m.selectedProc = -1
 				m.statusMsg = "Selection cleared"
+			} else if m.cfg.ConfirmQuit {
+				m.confirmingQuit = true
 			} else {
-				m.ctxCancel()
-				return m, tea.Quit
+				return m, m.quit()
 			}
-		case "tab":
-			m.activeTab = (m.activeTab + 1) % 3 // Now 3 tabs
-		case "h", "?":
-			m.showHelp = !m.showHelp
-		case "s":
-			if m.sortKey == "cpu" {
-				m.sortKey = "mem"
-			} else if m.sortKey == "mem" {
-				m.sortKey = "io"
-			} else if m.sortKey == "io" {
-				m.sortKey = "fd"
+		case "next_tab":
+			if m.minimal {
+				m.focusedPanel = (m.focusedPanel + 1) % len(minimalPanels)
+				break
+			}
+			m.activeTab = (m.activeTab + 1) % len(tabNames)
+		case "prev_panel":
+			if m.minimal {
+				m.focusedPanel = (m.focusedPanel + len(minimalPanels) - 1) % len(minimalPanels)
+			}
+		case "minimal":
+			m.minimal = !m.minimal
+			m.clampTopOffset()
+			if m.minimal {
+				m.statusMsg = "Minimal mode (tab: next panel, F: full UI)"
 			} else {
-				m.sortKey = "cpu"
+				m.statusMsg = "Full dashboard"
 			}
+		case "help":
+			m.showHelp = !m.showHelp
+		case "sort":
+			m.sortKey = nextSortKey(m.sortKey)
 			m.topOffset = 0
 			m.statusMsg = fmt.Sprintf("Sort: %s", strings.ToUpper(m.sortKey))
-		case "g":
+		case "sort2":
+			// "" (automatic) -> each key -> back to automatic
+			if m.sortKey2 == sortKeys[len(sortKeys)-1] {
+				m.sortKey2 = ""
+			} else {
+				m.sortKey2 = nextSortKey(m.sortKey2)
+			}
+			m.statusMsg = fmt.Sprintf("Secondary sort: %s", strings.ToUpper(m.secondarySortKey()))
+		case "reverse":
+			m.sortAsc = !m.sortAsc
+			m.topOffset = 0
+			if m.sortAsc {
+				m.statusMsg = "Sort: ascending"
+			} else {
+				m.statusMsg = "Sort: descending"
+			}
+		case "gpu":
 			m.showGPU = !m.showGPU
 			m.statusMsg = fmt.Sprintf("GPU panels %s", onOff(m.showGPU))
-		case "b":
+			if m.sampler != nil {
+				// Hidden panels don't need nvidia-smi running every poll
+				m.sampler.SetGPU(m.showGPU)
+			}
+		case "battery":
 			m.showBatt = !m.showBatt
 			m.statusMsg = fmt.Sprintf("Battery panel %s", onOff(m.showBatt))
-		case "i":
+			if m.sampler != nil {
+				m.sampler.SetBattery(m.showBatt)
+			}
+		case "io_panels":
 			m.showIOPanels = !m.showIOPanels
 			m.statusMsg = fmt.Sprintf("IO/FD panels %s", onOff(m.showIOPanels))
-		case "t":
+		case "side_panel":
+			// Minimal mode cycles the focused slot; the dashboard the lower one
+			slot := 1
+			if m.minimal && m.focusedPanel == 2 {
+				slot = 0
+			}
+			m.cycleSidePanel(slot)
+			m.showIOPanels = true
+		case "temps":
 			m.showTemps = !m.showTemps
 			m.statusMsg = fmt.Sprintf("Temps panel %s", onOff(m.showTemps))
-		case "n":
+		case "inotify":
 			m.showInotify = !m.showInotify
 			m.statusMsg = fmt.Sprintf("Inotify panel %s", onOff(m.showInotify))
-		case "c":
+		case "cgroups":
 			m.showCgroups = !m.showCgroups
 			m.statusMsg = fmt.Sprintf("Cgroups panel %s", onOff(m.showCgroups))
-		case "m":
+		case "mouse":
 			m.mouseEnabled = !m.mouseEnabled
 			m.statusMsg = fmt.Sprintf("Mouse %s", onOff(m.mouseEnabled))
-		case "f":
+			return m, mouseMode(m.mouseEnabled)
+		case "freeze":
 			m.paused = !m.paused
+			m.skipGapCheck = !m.paused
+			m.stepPending = false
 			m.statusMsg = fmt.Sprintf("Updates %s", onOff(!m.paused))
-		case "I":
+		case "step":
+			if !m.paused {
+				m.paused = true
+				m.statusMsg = "Updates off · . to step"
+				break
+			}
+			m.stepPending = true
+			m.stepAt = time.Now()
+			m.skipGapCheck = true
+			m.statusMsg = "Stepping…"
+		case "min_cpu":
+			m.cfg.MinCPU = nextPreset([]float64{0, 0.5, 1, 5, 10, 25}, m.cfg.MinCPU)
+			m.topOffset = 0
+			m.statusMsg = fmt.Sprintf("Min CPU: %g%%", m.cfg.MinCPU)
+		case "min_mem":
+			m.cfg.MinMem = nextPreset([]float64{0, 0.5, 1, 5, 10}, m.cfg.MinMem)
+			m.topOffset = 0
+			m.statusMsg = fmt.Sprintf("Min MEM: %g%%", m.cfg.MinMem)
+		case "cgroup_view":
+			m.cgroupView = (m.cgroupView + 1) % len(cgroupViews)
+			m.statusMsg = fmt.Sprintf("Cgroup panel: by %s", cgroupViews[m.cgroupView])
+		case "netstates":
+			if m.remote != nil {
+				m.statusMsg = "Socket states are fixed by the remote side (--netstates)"
+				break
+			}
+			if !m.linuxPanels() {
+				m.statusMsg = "Socket states need Linux /proc"
+				break
+			}
+			m.cfg.NetStates = !m.cfg.NetStates
+			m.sampler.SetNetStates(m.cfg.NetStates)
+			m.statusMsg = fmt.Sprintf("Socket states %s", onOff(m.cfg.NetStates))
+		case "kthreads":
+			if m.remote != nil {
+				m.statusMsg = "Kernel threads are fixed by the remote side (--kthreads)"
+				break
+			}
+			m.cfg.KernelThreads = !m.cfg.KernelThreads
+			m.sampler.SetKernelThreads(m.cfg.KernelThreads)
+			m.statusMsg = fmt.Sprintf("Kernel threads %s (next sample)", onOff(m.cfg.KernelThreads))
+		case "states":
+			next := stateFilters[0]
+			for i, f := range stateFilters {
+				if f == m.cfg.States {
+					next = stateFilters[(i+1)%len(stateFilters)]
+				}
+			}
+			m.cfg.States = next
+			m.topOffset = 0
+			m.statusMsg = fmt.Sprintf("Process states: %s", m.cfg.States)
+		case "columns":
+			m.showColumnChooser = true
+			m.columnCursor = 0
+		case "highlight":
+			m.highlightMode = !m.highlightMode
+			m.clampTopOffset()
+			m.statusMsg = fmt.Sprintf("Search highlight %s", onOff(m.highlightMode))
+		case "kill_filtered":
+			if m.remote != nil {
+				m.statusMsg = "Bulk kill is disabled for remote hosts"
+			} else if m.filter == "" {
+				m.statusMsg = "Bulk kill needs an active filter (/)"
+			} else if procs := m.filterMatches(m.latest.Top); len(procs) == 0 {
+				m.statusMsg = "No processes match filter"
+			} else {
+				m.killTargets, m.killSpared = nil, nil
+				for _, p := range procs {
+					if m.guard.Protects(p) {
+						m.killSpared = append(m.killSpared, p)
+					} else {
+						m.killTargets = append(m.killTargets, p)
+					}
+				}
+				if len(m.killTargets) == 0 {
+					m.statusMsg = fmt.Sprintf("All %d matching processes are protected (protect in config)", len(procs))
+					m.killSpared = nil
+				} else {
+					m.killStage = 1
+				}
+			}
+		case "pin":
+			m.togglePin()
+		case "rollup":
+			m.rollup = !m.rollup
+			m.selectedProc = -1
+			m.clampTopOffset()
+			if m.rollup {
+				m.statusMsg = "Grouped by command (Enter expands a group)"
+			} else {
+				m.statusMsg = "Rollup off"
+			}
+		case "bell":
+			if m.cfg.Bell <= 0 {
+				m.statusMsg = "Bell is off (start with -bell N)"
+				break
+			}
+			m.bellMuted = !m.bellMuted
+			if m.bellMuted {
+				m.statusMsg = "Bell muted"
+			} else {
+				m.statusMsg = "Bell unmuted"
+			}
+		case "cpu_norm":
+			m.cfg.CPUNorm = !m.cfg.CPUNorm
+			if m.cfg.CPUNorm {
+				m.statusMsg = "Process CPU: % of whole machine (CPU/N)"
+			} else {
+				m.statusMsg = "Process CPU: % of one core (may exceed 100)"
+			}
+		case "gradient":
+			m.sparkGradient = !m.sparkGradient
+			if m.sparkGradient {
+				m.statusMsg = "Sparklines colored by value"
+			} else {
+				m.statusMsg = "Sparklines colored per metric"
+			}
+		case "trend":
+			m.trendMetric = (m.trendMetric + 1) % len(trendMetrics)
+			m.statusMsg = fmt.Sprintf("Trend chart: %s", trendMetrics[m.trendMetric].name)
+		case "lifetime":
+			m.shameLifetime = !m.shameLifetime
+			if m.shameLifetime {
+				m.statusMsg = "Hall of Shame: lifetime CPU time"
+			} else {
+				m.statusMsg = "Hall of Shame: CPU since sysmoni started"
+			}
+		case "name_mode":
+			next := nameModes[0]
+			for i, mode := range nameModes {
+				if mode == m.nameMode {
+					next = nameModes[(i+1)%len(nameModes)]
+				}
+			}
+			m.nameMode = next
+			m.statusMsg = fmt.Sprintf("Command display: %s", m.nameMode)
+		case "baseline":
+			m.baselineAt = m.latest.Timestamp
+			m.baselineByPID = make(map[int]model.Process, len(m.latest.Top))
+			for _, p := range m.latest.Top {
+				m.baselineByPID[p.PID] = p
+			}
+			m.statusMsg = fmt.Sprintf("Baseline captured at %s (] to exit diff)", m.clock(m.baselineAt))
+		case "baseline_off":
+			if m.baselineByPID != nil {
+				m.baselineByPID = nil
+				m.statusMsg = "Diff mode off"
+			}
+		case "faster":
+			m.setInterval(m.cfg.Interval / 2)
+		case "interval_preset":
+			m.setInterval(nextInterval(m.cfg.Interval))
+		case "slower":
+			m.setInterval(m.cfg.Interval * 2)
+		case "export_csv":
+			if path, err := m.exportHistoryCSV(); err != nil {
+				m.statusMsg = fmt.Sprintf("CSV export failed: %v", err)
+			} else {
+				m.statusMsg = fmt.Sprintf("History exported: %s", path)
+			}
+		case "screenshot":
+			if txt, svg, err := m.exportScreen(); err != nil {
+				m.statusMsg = fmt.Sprintf("Screen export failed: %v", err)
+			} else {
+				m.statusMsg = fmt.Sprintf("Screen saved: %s, %s", txt, svg)
+			}
+		case "ionice_tip":
 			if len(m.latest.Top) > 0 {
 				p := m.latest.Top[0]
-				m.statusMsg = fmt.Sprintf("ionice tip: sudo ionice -c3 -p %d  (# %s)", p.PID, truncate(p.Command, 16))
+				m.statusMsg = fmt.Sprintf("ionice tip: sudo ionice -c3 -p %d  (# %s; i in its detail view runs it)", p.PID, truncate(p.Command, 16))
 			} else {
 				m.statusMsg = "ionice tip: sudo ionice -c3 -p <pid>"
 			}
-		case "/":
+		case "filter":
 			m.inputMode = true
 			m.inputBuf = nil
+			m.filterHistIdx = len(m.filterHist)
 			m.topOffset = 0
-		case "o":
+		case "search":
+			m.startSearch()
+		case "search_next":
+			m.nextSearchMatch()
+		case "json":
 			if m.jsonFile != "" {
+				m.closeJSON()
 				m.jsonFile = ""
 				m.statusMsg = "JSON output disabled"
 			} else if f := os.Getenv("SRPS_SYSMONI_JSON_FILE"); f != "" {
 				m.jsonFile = f
 				m.statusMsg = fmt.Sprintf("JSON output: %s", f)
 			}
-		case "enter":
+		case "detail":
 			// Show process detail modal for selected process
 			if m.selectedProc >= 0 {
-				procs := m.sortAndFilter(m.latest.Top)
+				procs := m.topRows(m.latest)
+				if m.toggleRollupGroup(procs, m.selectedProc) {
+					break
+				}
 				if m.selectedProc < len(procs) {
-					m.detailPID = procs[m.selectedProc].PID
-					m.showProcDetail = true
+					m.openDetail(procs[m.selectedProc].PID)
 				}
 			} else if len(m.latest.Top) > 0 {
 				// Show detail for top process
-				m.detailPID = m.latest.Top[0].PID
-				m.showProcDetail = true
+				m.openDetail(m.latest.Top[0].PID)
 			}
-		case "down", "j":
+		case "down":
 			if m.selectedProc >= 0 {
-				procs := m.sortAndFilter(m.latest.Top)
+				procs := m.topRows(m.latest)
 				if m.selectedProc < len(procs)-1 {
 					m.selectedProc++
 					// Auto-scroll if needed
@@ -377,7 +1040,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.bumpTopOffset(1)
 			}
-		case "up", "k":
+		case "up":
 			if m.selectedProc >= 0 {
 				if m.selectedProc > 0 {
 					m.selectedProc--
@@ -389,35 +1052,68 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.bumpTopOffset(-1)
 			}
-		case "pgdown", "J":
+		case "page_down":
 			m.bumpTopOffset(m.visibleTopPage())
-		case "pgup", "K":
+		case "page_up":
 			m.bumpTopOffset(-m.visibleTopPage())
 		case "end":
 			m.jumpTopEnd()
 		case "home":
 			m.topOffset = 0
-		case "1":
+		case "tab1":
 			m.activeTab = 0
-		case "2":
+		case "tab2":
 			m.activeTab = 1
-		case "3":
+		case "tab3":
 			m.activeTab = 2
+		case "tab4":
+			m.activeTab = 3
 		}
+	case clipboardMsg:
+		m.detailNote, m.detailErr = fmt.Sprintf("✓ Copied %s (%s)", msg.what, msg.how), false
+		m.statusMsg = m.detailNote
 	case tickMsg:
 		m.tickCount++
-		if m.paused {
+		if m.paused && !m.stepPending {
 			return m, tickCmd()
 		}
 		select {
 		case samp, ok := <-m.stream:
-			if ok {
+			if ok && m.stepPending && samp.Timestamp.Before(m.stepAt.Add(-samp.Interval)) {
+				// Taken while frozen and left waiting in the stream; the
+				// sampler's next tick is already due
+				break
+			}
+			if !ok {
+				// A remote stream ends when the connection drops, a local
+				// one only when the sampler panicked
+				m.stream = nil
+				if m.remote != nil {
+					m.statusMsg = fmt.Sprintf("Remote disconnected: %v", m.remote.Err())
+				} else if err := m.sampler.Err(); err != nil {
+					m.fatalErr = err
+					return m, m.quit()
+				}
+			} else {
+				m.checkGap(samp)
+				m.maybeWriteJSON(samp) // raw values, before smoothing
+				m.retained.Add(samp)
+				samp = m.smooth.apply(samp)
 				m.latest = samp
 				m.recordHistory(samp)
 				m.updateStats(samp)
+				m.updateFlash(samp)
 				m.updateAlerts(samp)
-				m.maybeWriteJSON(samp)
+				m.updateWatchdog(samp)
+				m.updatePins(samp)
+				m.updateFocus(samp)
+				m.checkAccess(samp.Access)
 				m.clampTopOffset()
+				m.placeDashboard()
+				if m.stepPending {
+					m.stepPending = false
+					m.statusMsg = "Stepped to " + m.clock(samp.Timestamp)
+				}
 			}
 		default:
 		}
@@ -426,20 +1122,154 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// updateAlerts checks for critical conditions and updates alert state
-func (m *Model) updateAlerts(s model.Sample) {
-	m.alertCount = 0
-	m.criticalCPU = s.CPU.Total > 90
-	m.criticalMem = pct(s.Memory.UsedBytes, s.Memory.TotalBytes) > 90
-	m.criticalSwap = pct(s.Memory.SwapUsed, s.Memory.SwapTotal) > 80
-	m.criticalTemp = false
+// openDetail shows the detail modal for pid, reading its cmdline/env once
+// here rather than every sample.
+func (m *Model) openDetail(pid int) {
+	m.detailPID = pid
+	m.showProcDetail = true
+	m.detailNote, m.detailErr, m.tipArmed = "", false, ""
+	if m.remote == nil && m.detailInfo.PID != pid {
+		m.detailInfo = sampler.Inspect(pid)
+		m.detailInfo.Cmdline = m.redactor.Command(m.detailInfo.Cmdline)
+	}
+}
 
-	for _, t := range s.Temps {
-		if t.Temp > 85 {
-			m.criticalTemp = true
+// stepDetail moves the detail modal to the next/previous process in the
+// current sorted and filtered list, keeping the table selection in sync.
+func (m *Model) stepDetail(delta int) {
+	procs := m.topRows(m.latest)
+	if len(procs) == 0 {
+		return
+	}
+	idx := -1
+	for i, p := range procs {
+		if p.PID == m.detailPID {
+			idx = i
 			break
 		}
 	}
+	if idx < 0 {
+		// Detail process vanished; resume from the old selection
+		idx = max(m.selectedProc, 0) - delta
+	}
+	idx = min(max(idx+delta, 0), len(procs)-1)
+	// Group rows have no process to show; step past them
+	for isRollup(procs[idx]) {
+		next := idx + delta
+		if next < 0 || next >= len(procs) {
+			return
+		}
+		idx = next
+	}
+	m.openDetail(procs[idx].PID)
+	m.selectRow(idx)
+}
+
+// checkGap counts intervals missing between the previous sample and samp and
+// folds the gap into the measured interval.
+func (m *Model) checkGap(samp model.Sample) {
+	prev := m.latest.Timestamp
+	if m.skipGapCheck || m.latest.Interval <= 0 || prev.IsZero() {
+		m.skipGapCheck = false
+		return
+	}
+	gap := samp.Timestamp.Sub(prev)
+	if m.measuredInterval == 0 {
+		m.measuredInterval = gap
+	} else {
+		m.measuredInterval = (3*m.measuredInterval + gap) / 4
+	}
+	if missed := int(gap/samp.Interval) - 1; gap > samp.Interval*3/2 && missed > 0 {
+		m.droppedSamples += missed
+		m.lastDropAt = time.Now()
+	}
+}
+
+// remoteArgs forwards the sampling options that the remote side must apply.
+func remoteArgs(cfg config.Config) []string {
+	args := []string{
+		"-interval", cfg.Interval.String(),
+		fmt.Sprintf("-top-n=%d", cfg.TopN),
+		fmt.Sprintf("-throttled-n=%d", cfg.ThrottledN),
+		fmt.Sprintf("-netstates=%t", cfg.NetStates),
+		fmt.Sprintf("-kthreads=%t", cfg.KernelThreads),
+		fmt.Sprintf("-adaptive=%t", cfg.Adaptive),
+		fmt.Sprintf("-light=%t", cfg.Light),
+		fmt.Sprintf("-container-names=%t", cfg.ContainerNames),
+		fmt.Sprintf("-gpu=%t", cfg.EnableGPU),
+		fmt.Sprintf("-battery=%t", cfg.EnableBatt),
+		"-gpu-interval", cfg.GPUInterval.String(),
+		"-jitter", cfg.Jitter.String(),
+		fmt.Sprintf("-redact=%t", cfg.Redact),
+		fmt.Sprintf("-pid=%d", cfg.FocusPID),
+		"-track=" + cfg.Track,
+		"-json-fields=", // the TUI needs whole samples whatever the remote config says
+	}
+	filters := []struct {
+		flag  string
+		globs []string
+	}{
+		{"disk-include", cfg.DiskInclude}, {"disk-exclude", cfg.DiskExclude},
+		{"net-include", cfg.NetInclude}, {"net-exclude", cfg.NetExclude},
+		{"redact-keys", cfg.RedactKeys},
+	}
+	for _, f := range filters {
+		if len(f.globs) > 0 {
+			args = append(args, "-"+f.flag+"="+strings.Join(f.globs, ","))
+		}
+	}
+	return args
+}
+
+// setInterval clamps d to the supported range and applies it to the sampler.
+func (m *Model) setInterval(d time.Duration) {
+	if m.remote != nil {
+		m.statusMsg = "Interval is fixed for remote hosts (--interval at start)"
+		return
+	}
+	if d < minInterval {
+		d = minInterval
+	}
+	if d > maxInterval {
+		d = maxInterval
+	}
+	m.cfg.Interval = d
+	m.sampler.SetInterval(d)
+	m.measuredInterval = 0
+	m.skipGapCheck = true
+	m.statusMsg = fmt.Sprintf("Interval: %s", d)
+}
+
+// updateAlerts checks for critical conditions and updates alert state. A
+// metric turns critical above its threshold but only clears once it drops
+// AlertHysteresis below it, so a value hovering at the line doesn't flicker.
+func (m *Model) updateAlerts(s model.Sample) {
+	wasCPU, wasMem, wasSwap, wasTemp, wasFD := m.criticalCPU, m.criticalMem, m.criticalSwap, m.criticalTemp, m.criticalFD
+	wasStuck := m.criticalStuck
+	critical := func(was bool, v, threshold float64) bool {
+		if was {
+			return v > threshold-m.cfg.AlertHysteresis
+		}
+		return v > threshold
+	}
+	maxT := 0.0
+	for _, t := range s.Temps {
+		maxT = math.Max(maxT, t.Temp)
+	}
+	m.alertCount = 0
+	m.criticalCPU = critical(wasCPU, s.CPU.Total, 90)
+	m.criticalMem = critical(wasMem, pct(s.Memory.UsedBytes, s.Memory.TotalBytes), 90)
+	m.criticalSwap = critical(wasSwap, pct(s.Memory.SwapUsed, s.Memory.SwapTotal), 80)
+	m.criticalTemp = critical(wasTemp, maxT, 85)
+	m.criticalFD = critical(wasFD, s.Limits.FilesPct(), 90)
+	// A pileup clears once half the stuck processes have woken up
+	if n := m.cfg.StuckAlert; n <= 0 {
+		m.criticalStuck = false
+	} else if wasStuck {
+		m.criticalStuck = s.Tasks.Stuck > n/2
+	} else {
+		m.criticalStuck = s.Tasks.Stuck >= n
+	}
 
 	if m.criticalCPU {
 		m.alertCount++
@@ -453,15 +1283,139 @@ func (m *Model) updateAlerts(s model.Sample) {
 	if m.criticalTemp {
 		m.alertCount++
 	}
+	if m.criticalFD {
+		m.alertCount++
+	}
+	if m.criticalStuck {
+		m.alertCount++
+	}
+
+	// Notify only on rising edges; the hook debounces flapping metrics.
+	// During quiet hours only the QuietAlways metrics ring the bell.
+	for metric, rising := range map[string]bool{
+		"cpu": m.criticalCPU && !wasCPU, "mem": m.criticalMem && !wasMem, "swap": m.criticalSwap && !wasSwap,
+		"temp": m.criticalTemp && !wasTemp, "fd": m.criticalFD && !wasFD, "stuck": m.criticalStuck && !wasStuck,
+	} {
+		if rising && !m.alertHook.Quieted(metric, s.Timestamp) {
+			m.ringBell()
+			break
+		}
+	}
+	if m.criticalCPU && !wasCPU {
+		m.fireAlert(s, "cpu", s.CPU.Total, "CPU critical: %.0f%%")
+	}
+	if m.criticalMem && !wasMem {
+		m.fireAlert(s, "mem", pct(s.Memory.UsedBytes, s.Memory.TotalBytes), "Memory critical: %.0f%% used")
+	}
+	if m.criticalSwap && !wasSwap {
+		m.fireAlert(s, "swap", pct(s.Memory.SwapUsed, s.Memory.SwapTotal), "Swap critical: %.0f%% used")
+	}
+	if m.criticalTemp && !wasTemp {
+		m.fireAlert(s, "temp", maxT, "Temperature critical: %.0f°C")
+	}
+	if m.criticalFD && !wasFD {
+		m.fireAlert(s, "fd", s.Limits.FilesPct(), "File handles critical: %.0f%% of fs.file-max in use")
+	}
+	if m.criticalStuck && !wasStuck {
+		m.sendAlert(s.Timestamp, "stuck", float64(s.Tasks.Stuck), fmt.Sprintf("D-state pileup: %d processes in uninterruptible sleep: %s",
+			s.Tasks.Stuck, stuckNames(s.Stuck, 5)))
+	}
+}
+
+// accessHintShare is the share of processes with unreadable IO or FD counters
+// at which checkAccess suggests running as root.
+const accessHintShare = 0.2
+
+// checkAccess puts a one-time hint in the status line when many processes'
+// IO or FD counters were denied, so their zeroed columns aren't taken at face
+// value.
+func (m *Model) checkAccess(a model.Access) {
+	if m.accessHinted || a.Checked == 0 {
+		return
+	}
+	denied := max(a.IODenied, a.FDDenied)
+	if float64(denied) < accessHintShare*float64(a.Checked) {
+		return
+	}
+	m.accessHinted = true
+	who := "Run as root"
+	if m.remote != nil {
+		who = "Run the remote sysmoni as root"
+	}
+	m.statusMsg = fmt.Sprintf("%s for full I/O stats: %d/%d processes unreadable (shown as -)", who, denied, a.Checked)
+}
+
+// ringBell writes Bell BEL characters to the terminal; one ring per
+// sample however many metrics turned critical at once.
+func (m *Model) ringBell() {
+	if m.cfg.Bell <= 0 || m.bellMuted {
+		return
+	}
+	_, _ = m.out.WriteString(strings.Repeat("\a", m.cfg.Bell))
+}
+
+func (m *Model) fireAlert(s model.Sample, metric string, value float64, format string) {
+	m.sendAlert(s.Timestamp, metric, value, fmt.Sprintf(format, value))
+}
+
+func (m *Model) sendAlert(ts time.Time, metric string, value float64, msg string) {
+	if host, _ := os.Hostname(); host != "" {
+		msg = host + ": " + msg
+	}
+	m.alertHook.Fire(alert.Event{Metric: metric, Message: msg, Value: value, Timestamp: ts})
+}
+
+// watchdogLogSize caps the actions kept for the Analysis tab.
+const watchdogLogSize = 50
+
+// updateWatchdog feeds the sample to the watchdog; each action goes to the
+// status line, the Analysis log and the alert hook (debounced per PID).
+func (m *Model) updateWatchdog(s model.Sample) {
+	if m.watchdog == nil {
+		return
+	}
+	for _, a := range m.watchdog.Observe(s.Top, s.Timestamp) {
+		m.watchdogLog = append(m.watchdogLog, a)
+		m.statusMsg = "Watchdog: " + a.String()
+		m.sendAlert(a.Timestamp, fmt.Sprintf("watchdog:%d", a.PID), a.CPU, "Watchdog "+a.String())
+	}
+	if n := len(m.watchdogLog); n > watchdogLogSize {
+		m.watchdogLog = append([]watchdog.Action(nil), m.watchdogLog[n-watchdogLogSize:]...)
+	}
 }
 
 func (m *Model) updateStats(s model.Sample) {
-	// Accumulate CPU integral (CPU% * interval_seconds)
-	// Approximate interval as 1s or use s.Interval if precise
+	// Accumulate CPU integral (CPU% * seconds since the previous sample);
+	// the interval itself drifts with jitter and -adaptive
 	factor := s.Interval.Seconds()
+	if !m.statsAt.IsZero() && s.Timestamp.After(m.statsAt) {
+		factor = s.Timestamp.Sub(m.statsAt).Seconds()
+	}
+	m.statsAt = s.Timestamp
 
+	seen := make(map[int]bool, len(s.Top))
 	for _, p := range s.Top {
+		seen[p.PID] = true
 		m.cumulativeCPU[p.Command] += p.CPU * factor
+		// A new command, or CPU time going backwards, means the PID was reused
+		if prev, ok := m.lifetimeCPU[p.PID]; ok && (prev.command != p.Command || p.CPUTimeSeconds < prev.seconds) {
+			m.lifetimeGone[prev.command] += prev.seconds
+		}
+		m.lifetimeCPU[p.PID] = lifetimeEntry{command: p.Command, seconds: p.CPUTimeSeconds, seen: s.Timestamp}
+	}
+	// Fold exited PIDs into their command's total. Ones merely out of the
+	// top list keep their entry, so their time isn't counted twice when they
+	// return; remote PIDs can't be checked and are folded once unseen for
+	// lifetimeStale.
+	for pid, e := range m.lifetimeCPU {
+		if seen[pid] {
+			continue
+		}
+		if m.remote == nil && sampler.Alive(pid) || m.remote != nil && s.Timestamp.Sub(e.seen) < lifetimeStale {
+			continue
+		}
+		m.lifetimeGone[e.command] += e.seconds
+		delete(m.lifetimeCPU, pid)
 	}
 	for _, p := range s.Throttled {
 		m.throttleCount[p.Command]++
@@ -469,23 +1423,20 @@ func (m *Model) updateStats(s model.Sample) {
 }
 
 func (m *Model) recordHistory(s model.Sample) {
-	appendHist := func(hist []float64, val float64) []float64 {
-		hist = append(hist, val)
-		if len(hist) > historyPoints {
-			hist = hist[len(hist)-historyPoints:]
-		}
-		return hist
+	m.timeHist = append(m.timeHist, s.Timestamp)
+	if len(m.timeHist) > historyPoints {
+		m.timeHist = m.timeHist[len(m.timeHist)-historyPoints:]
 	}
-
-	m.cpuHist = appendHist(m.cpuHist, s.CPU.Total)
+	m.cpuHist = appendHistory(m.cpuHist, s.CPU.Total)
 
 	memPct := pct(s.Memory.UsedBytes, s.Memory.TotalBytes)
-	m.memHist = appendHist(m.memHist, memPct)
+	m.memHist = appendHistory(m.memHist, memPct)
+	m.swapHist = appendHistory(m.swapHist, pct(s.Memory.SwapUsed, s.Memory.SwapTotal))
 
-	m.netRxHist = appendHist(m.netRxHist, s.IO.NetRxMbps)
-	m.netTxHist = appendHist(m.netTxHist, s.IO.NetTxMbps)
-	m.diskReadHist = appendHist(m.diskReadHist, s.IO.DiskReadMBs)
-	m.diskWriteHist = appendHist(m.diskWriteHist, s.IO.DiskWriteMBs)
+	m.netRxHist = appendHistory(m.netRxHist, s.IO.NetRxMbps)
+	m.netTxHist = appendHistory(m.netTxHist, s.IO.NetTxMbps)
+	m.diskReadHist = appendHistory(m.diskReadHist, s.IO.DiskReadMBs)
+	m.diskWriteHist = appendHistory(m.diskWriteHist, s.IO.DiskWriteMBs)
 
 	for i, v := range s.CPU.PerCore {
 		buf := m.perCoreHist[i]
@@ -495,6 +1446,16 @@ func (m *Model) recordHistory(s model.Sample) {
 		}
 		m.perCoreHist[i] = buf
 	}
+
+	for i, g := range s.GPUs {
+		m.gpuUtilHist[i] = appendHistory(m.gpuUtilHist[i], g.Util)
+		vram := 0.0
+		if g.MemTotalMB > 0 {
+			vram = g.MemUsedMB / g.MemTotalMB * 100
+		}
+		m.gpuMemHist[i] = appendHistory(m.gpuMemHist[i], vram)
+	}
+	m.recordDeviceHistory(s)
 }
 
 func (m *Model) View() string {
@@ -503,7 +1464,17 @@ func (m *Model) View() string {
 	}
 	s := m.latest
 
-	// Show process detail modal overlay if active
+	if m.confirmingQuit {
+		return m.renderQuitConfirmModal()
+	}
+	if m.killStage > 0 {
+		return m.renderKillConfirmModal()
+	}
+	if m.showColumnChooser {
+		return m.renderColumnChooser()
+	}
+
+	// Show process detail modal overlay if active
 	if m.showProcDetail {
 		return m.renderProcDetailModal(s)
 	}
@@ -512,11 +1483,64 @@ func (m *Model) View() string {
 		return m.renderHelp()
 	}
 
+	header := m.renderHeader(s)
+
+	// Content based on tab
+	var content string
+	switch {
+	case m.minimal:
+		content = m.renderMinimal(s)
+	case m.activeTab == 0:
+		content, _ = m.layoutDashboard(s, lipgloss.Height(header))
+	case m.activeTab == 1:
+		content = m.renderAnalysis(s)
+	case m.activeTab == 2:
+		content = m.renderSystemInfo(s)
+	case m.activeTab == 3:
+		content = m.renderThroughput(s)
+	}
+
+	// Enhanced footer with keyboard hints and status
+	footerLeft := subtleStyle.Render("tab/1-4:view  s:sort  /:filter  ?:help")
+	if m.minimal {
+		footerLeft = subtleStyle.Render("tab:panel  F:full  ?:help")
+	}
+	toggles := fmt.Sprintf("g:%s i:%s t:%s b:%s",
+		onOffIcon(m.showGPU), onOffIcon(m.showIOPanels), onOffIcon(m.showTemps), onOffIcon(m.showBatt))
+	footerMid := subtleStyle.Render(toggles)
+	if m.minimal {
+		footerMid = ""
+	}
+	footerRight := ""
+	if m.statusMsg != "" {
+		footerRight = lipgloss.NewStyle().Foreground(lipgloss.Color(primaryColor)).Render(m.statusMsg)
+	}
+
+	footerGap := m.width - lipgloss.Width(footerLeft) - lipgloss.Width(footerMid) - lipgloss.Width(footerRight) - 4
+	if footerGap < 1 {
+		footerGap = 1
+	}
+
+	footer := lipgloss.JoinHorizontal(lipgloss.Bottom,
+		footerLeft,
+		strings.Repeat(" ", footerGap/2),
+		footerMid,
+		strings.Repeat(" ", footerGap-footerGap/2),
+		footerRight)
+
+	return m.withTooltip(lipgloss.JoinVertical(lipgloss.Left, header, content, footer))
+}
+
+// renderHeader is the title bar: tabs, filter, alert badge and clock.
+func (m *Model) renderHeader(s model.Sample) string {
 	// --- Header with Tabs and Alert Badge ---
 	filterTxt := ""
 	if m.filter != "" || m.inputMode {
 		filterTxt = fmt.Sprintf(" /: %s", displayFilter(m))
 	}
+	if m.searchMode {
+		filterTxt += " :" + string(m.searchBuf)
+	}
 
 	// Tab Styles with glow effect for active
 	activeTabStyle := lipgloss.NewStyle().
@@ -529,7 +1553,7 @@ func (m *Model) View() string {
 		Background(lipgloss.Color("#333333")).
 		Padding(0, 1)
 
-	tabs := []string{" 1:Dashboard ", " 2:Analysis ", " 3:System "}
+	tabs := []string{" 1:Dashboard ", " 2:Analysis ", " 3:System ", " 4:Throughput "}
 	var tabRenders []string
 	for i, t := range tabs {
 		if i == m.activeTab {
@@ -542,19 +1566,35 @@ func (m *Model) View() string {
 
 	// Status indicators with icons
 	sortIcon := "▼"
+	if m.sortAsc {
+		sortIcon = "▲"
+	}
 	switch m.sortKey {
 	case "mem":
-		sortIcon = "▼M"
+		sortIcon += "M"
 	case "io":
-		sortIcon = "▼I"
+		sortIcon += "I"
 	case "fd":
-		sortIcon = "▼F"
+		sortIcon += "F"
+	case "conn":
+		sortIcon += "N"
+	case "oom":
+		sortIcon += "O"
+	case "dmem", "dfd":
+		sortIcon += "Δ"
+	case "majflt":
+		sortIcon += "P"
+	case "blkio":
+		sortIcon += "B"
 	default:
-		sortIcon = "▼C"
+		sortIcon += "C"
 	}
 	pauseIcon := ""
 	if m.paused {
 		pauseIcon = " ⏸"
+	} else if s.Interval > m.cfg.Interval {
+		// Adaptive sampling has backed off while the system is quiet
+		pauseIcon = fmt.Sprintf(" ⟳%s", s.Interval)
 	}
 
 	// Alert badge using pulseStyle with animation
@@ -568,11 +1608,42 @@ func (m *Model) View() string {
 		alertBadge = alertStyleLocal.Render(fmt.Sprintf("⚠ %d", m.alertCount))
 	}
 
-	info := subtleStyle.Render(fmt.Sprintf("%s%s%s%s", sortIcon, strings.ToUpper(m.sortKey), pauseIcon, filterTxt))
-	timestamp := subtleStyle.Render(s.Timestamp.Format("15:04:05"))
+	sort2 := ""
+	if m.sortKey2 != "" {
+		sort2 = "/" + strings.ToUpper(m.sortKey2)
+	}
+	hostTxt := ""
+	if m.remote != nil {
+		hostTxt = " @" + m.cfg.Remote
+	}
+	smoothTxt := ""
+	if m.smooth.enabled() {
+		smoothTxt = fmt.Sprintf(" ≈%g", m.smooth.alpha)
+	}
+	watchdogTxt := ""
+	if m.watchdog != nil {
+		watchdogTxt = " 🛡" + m.watchdog.Mode()
+	}
+	quietTxt := ""
+	if m.alertHook.Quiet.Contains(s.Timestamp) {
+		quietTxt = " 🌙quiet"
+	}
+	info := subtleStyle.Render(fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s", sortIcon, strings.ToUpper(m.sortKey), sort2, hostTxt, pauseIcon, smoothTxt, watchdogTxt, quietTxt, filterTxt, m.thresholdLabel()))
+	timestamp := subtleStyle.Render(m.headerClock(s))
+	if m.paused {
+		age := time.Since(s.Timestamp).Round(time.Second)
+		timestamp = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Bold(true).
+			Render(fmt.Sprintf("FROZEN (age %02d:%02d)", int(age.Minutes()), int(age.Seconds())%60))
+	} else if !m.lastDropAt.IsZero() && time.Since(m.lastDropAt) < 10*time.Second {
+		timestamp = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).
+			Render(fmt.Sprintf("⚠ %d dropped ", m.droppedSamples)) + timestamp
+	}
 
 	// Build header with proper spacing
 	leftPart := tabBar
+	if m.minimal {
+		leftPart = m.minimalPanelBar(activeTabStyle, inactiveTabStyle)
+	}
 	rightPart := lipgloss.JoinHorizontal(lipgloss.Center, alertBadge, " ", info, " ", timestamp)
 
 	gap := m.width - lipgloss.Width(leftPart) - lipgloss.Width(rightPart) - 2
@@ -585,42 +1656,12 @@ func (m *Model) View() string {
 		strings.Repeat(" ", gap),
 		rightPart)
 
-	header = headerStyle.Width(m.width).Render(header)
-
-	// Content based on tab
-	var content string
-	switch m.activeTab {
-	case 0:
-		content = m.renderDashboard(s)
-	case 1:
-		content = m.renderAnalysis(s)
-	case 2:
-		content = m.renderSystemInfo(s)
-	}
-
-	// Enhanced footer with keyboard hints and status
-	footerLeft := subtleStyle.Render("tab/1-3:view  s:sort  /:filter  ?:help")
-	toggles := fmt.Sprintf("g:%s i:%s t:%s b:%s",
-		onOffIcon(m.showGPU), onOffIcon(m.showIOPanels), onOffIcon(m.showTemps), onOffIcon(m.showBatt))
-	footerMid := subtleStyle.Render(toggles)
-	footerRight := ""
-	if m.statusMsg != "" {
-		footerRight = lipgloss.NewStyle().Foreground(lipgloss.Color(primaryColor)).Render(m.statusMsg)
-	}
-
-	footerGap := m.width - lipgloss.Width(footerLeft) - lipgloss.Width(footerMid) - lipgloss.Width(footerRight) - 4
-	if footerGap < 1 {
-		footerGap = 1
+	if m.minimal {
+		header = lipgloss.NewStyle().Width(m.width).Render(header)
+	} else {
+		header = headerStyle.Width(m.width).Render(header)
 	}
-
-	footer := lipgloss.JoinHorizontal(lipgloss.Bottom,
-		footerLeft,
-		strings.Repeat(" ", footerGap/2),
-		footerMid,
-		strings.Repeat(" ", footerGap-footerGap/2),
-		footerRight)
-
-	return lipgloss.JoinVertical(lipgloss.Left, header, content, footer)
+	return header
 }
 
 // onOffIcon returns a visual indicator for on/off state
@@ -631,46 +1672,133 @@ func onOffIcon(v bool) string {
 	return "○"
 }
 
-func (m *Model) renderDashboard(s model.Sample) string {
-	// --- Row 1: Vitals (CPU, MEM, SWAP, LOAD) ---
+// dashPlacement is where the dashboard put its cards on screen, for mouse
+// hit testing, and the height the process card got.
+type dashPlacement struct {
+	zones                 []hoverZone // gauges and sparklines with tooltips
+	procsTop, procsBottom int         // rows of the process card
+	procsHeight           int
+}
+
+// placeDashboard records where the dashboard's cards land below the header.
+func (m *Model) placeDashboard() {
+	m.placed = dashPlacement{}
+	if m.width == 0 || m.minimal {
+		return
+	}
+	_, m.placed = m.layoutDashboard(m.latest, lipgloss.Height(m.renderHeader(m.latest)))
+}
+
+// layoutDashboard stacks the layout's rows of cards and reports where they
+// land when the first row is at screen row top. The other rows are rendered
+// first, so the process card gets the height they leave.
+func (m *Model) layoutDashboard(s model.Sample, top int) (string, dashPlacement) {
+	var pl dashPlacement
+	layout := m.layout()
+	rows := make([]string, len(layout))
+	cards := make([][]string, len(layout))
+	procsRow, used := -1, 0
+	for r, names := range layout {
+		if names[0] == "procs" {
+			procsRow = r
+			continue
+		}
+		cards[r] = make([]string, len(names))
+		for i, name := range names {
+			cards[r][i] = dashCards[name].render(m, s) // validated in main
+		}
+		rows[r] = lipgloss.JoinHorizontal(lipgloss.Top, cards[r]...)
+		used += lipgloss.Height(rows[r])
+	}
+	// footer=1, padding=3; capped so the table doesn't grow without bound
+	pl.procsHeight = maxInt(6, minInt(20, m.height-top-used-4))
+	if procsRow >= 0 {
+		rows[procsRow] = m.renderProcsCard(s, pl.procsHeight)
+	}
+
+	y := top
+	for r, names := range layout {
+		if r == procsRow {
+			pl.procsTop, pl.procsBottom = y, y+lipgloss.Height(rows[r])
+		} else {
+			hover := make([][]string, len(names))
+			for i, name := range names {
+				hover[i] = dashCards[name].hover
+			}
+			pl.zones = append(pl.zones, hoverCards(y, cards[r], hover)...)
+		}
+		y += lipgloss.Height(rows[r])
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...), pl
+}
+
+func (m *Model) renderCPUCard(s model.Sample) string {
 	// CPU Section with gradient gauge
-	cpuGauge := renderGauge("CPU", s.CPU.Total) // Use convenient wrapper
-	cpuGraph := renderSparklinePct(m.cpuHist, 20, primaryColor)
+	cpuGauge := renderGauge(m.flash("cpu", "CPU"), s.CPU.Total) // Use convenient wrapper
+	cpuGraph := m.sparkPct(m.cpuHist, 20, primaryColor)
 	// Add pulsing critical badge when CPU is over 90%
 	cpuAlert := ""
 	if m.criticalCPU && m.tickCount%4 < 2 {
 		cpuAlert = " " + pulseStyle.Render("CRITICAL")
 	}
-	cpuBlock := lipgloss.JoinHorizontal(lipgloss.Bottom, cpuGauge, "  ", cpuGraph, cpuAlert)
+	cpuBlock := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Bottom, cpuGauge, "  ", cpuGraph, cpuAlert),
+		renderCPUWaits(s.CPU))
 	// Use alert border if critical
 	cpuCardStyle := cardStyle
 	if m.criticalCPU {
 		cpuCardStyle = alertCardStyle
 	}
-	cpuCard := cpuCardStyle.Render(cpuBlock)
+	return cpuCardStyle.Render(cpuBlock)
+}
 
+func (m *Model) renderMemCard(s model.Sample) string {
 	// Memory Section with gradient gauge
 	memVal := pct(s.Memory.UsedBytes, s.Memory.TotalBytes)
-	memGauge := renderGaugeEnhanced("MEM", memVal, "#BD93F9", true) // Use gradient
-	memGraph := renderSparklinePct(m.memHist, 20, "#BD93F9")
+	memGauge := renderGaugeEnhanced(m.flash("mem", "MEM"), memVal, "#BD93F9", true) // Use gradient
+	memGraph := m.sparkPct(m.memHist, 20, "#BD93F9")
 	// Add pulsing critical badge when MEM is over 90%
 	memAlert := ""
 	if m.criticalMem && m.tickCount%4 < 2 {
 		memAlert = " " + pulseStyle.Render("LOW MEM")
 	}
 	memDetails := subtleStyle.Render(fmt.Sprintf("%.1f/%.1f GB | cache %.1f GB | buf %.1f GB", bytesToGiB(s.Memory.UsedBytes), bytesToGiB(s.Memory.TotalBytes), bytesToGiB(s.Memory.Cached), bytesToGiB(s.Memory.Buffers)))
-	memBlock := lipgloss.JoinVertical(lipgloss.Left,
+	kernDetails := fmt.Sprintf("slab %s | dirty %s | wb %s", formatBytes(s.Memory.Slab), formatBytes(s.Memory.Dirty), formatBytes(s.Memory.Writeback))
+	if s.Memory.HugePagesTotal > 0 {
+		kernDetails += fmt.Sprintf(" | huge %d/%d free (%s)", s.Memory.HugePagesFree, s.Memory.HugePagesTotal, formatBytes(s.Memory.HugePageSize))
+	}
+	memLines := []string{
 		lipgloss.JoinHorizontal(lipgloss.Bottom, memGauge, "  ", memGraph, memAlert),
-		memDetails)
+		memDetails,
+		subtleStyle.Render(kernDetails),
+	}
+	if eta, perMin, ok := m.memTrend(); ok {
+		when := "<1m"
+		if eta >= time.Minute {
+			when = formatHM(int64(eta.Seconds()))
+		}
+		memLines = append(memLines, lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Bold(true).
+			Render(fmt.Sprintf("OOM in ~%s at current rate (+%.1f%%/min)", when, perMin)))
+	}
+	// Under memory pressure, name the process the OOM killer would pick first
+	if m.criticalMem {
+		if victim, ok := likelyOOMVictim(s.Top); ok {
+			memLines = append(memLines, criticalStyle.Render(fmt.Sprintf("next OOM victim: %s (PID %d, score %d)",
+				truncate(victim.Command, 20), victim.PID, victim.OOMScore)))
+		}
+	}
+	memBlock := lipgloss.JoinVertical(lipgloss.Left, memLines...)
 	memCardStyle := cardStyle
 	if m.criticalMem {
 		memCardStyle = alertCardStyle
 	}
-	memCard := memCardStyle.Render(memBlock)
+	return memCardStyle.Render(memBlock)
+}
 
+func (m *Model) renderSwapCard(s model.Sample) string {
 	// Swap & Load with gradient gauge
 	swapVal := pct(s.Memory.SwapUsed, s.Memory.SwapTotal)
-	swapGauge := renderGaugeEnhanced("SWAP", swapVal, warningColor, true) // Use gradient
+	swapGauge := renderGaugeEnhanced(m.flash("swap", "SWAP"), swapVal, warningColor, true) // Use gradient
 	// Add pulsing for critical swap
 	swapAlert := ""
 	if m.criticalSwap && m.tickCount%4 < 2 {
@@ -685,59 +1813,95 @@ func (m *Model) renderDashboard(s model.Sample) string {
 	}
 	loadValStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(loadColor)).Bold(true)
 	// Use miniGaugeStyle as container for load info
-	loadMiniGauge := miniGaugeStyle.Render("LOAD: ") + loadValStyle.Render(fmt.Sprintf("%.2f", s.CPU.Load1)) +
+	loadMiniGauge := miniGaugeStyle.Render("LOAD: ") + m.flash("load", loadValStyle.Render(fmt.Sprintf("%.2f", s.CPU.Load1))) +
 		subtleStyle.Render(fmt.Sprintf(" (%.0f cores) 5m %.2f 15m %.2f", float64(len(s.CPU.PerCore)), s.CPU.Load5, s.CPU.Load15))
-	miscBlock := lipgloss.JoinVertical(lipgloss.Left,
-		lipgloss.JoinHorizontal(lipgloss.Bottom, swapGauge, swapAlert),
-		loadMiniGauge)
+	// Load normalised by core count: 100% means every core has a runnable task
+	cores := float64(maxInt(1, len(s.CPU.PerCore)))
+	loadGauge := renderGauge("LOAD/CORE", s.CPU.Load1/cores*100)
+	loadNorm := subtleStyle.Render(fmt.Sprintf(" 5m %.0f%% 15m %.0f%%", s.CPU.Load5/cores*100, s.CPU.Load15/cores*100))
+	miscLines := []string{lipgloss.JoinHorizontal(lipgloss.Bottom, swapGauge, swapAlert)}
+	if s.Memory.SwapTotal > 0 || s.Memory.SwapInBps > 0 || s.Memory.SwapOutBps > 0 {
+		miscLines = append(miscLines, renderSwapIO(s.Memory))
+	}
+	miscLines = append(miscLines,
+		lipgloss.JoinHorizontal(lipgloss.Bottom, loadGauge, loadNorm),
+		loadMiniGauge,
+		renderTasks(s.Tasks, len(s.CPU.PerCore)),
+	)
+	if m.criticalStuck {
+		miscLines = append(miscLines, criticalStyle.Render("STUCK: "+stuckNames(s.Stuck, 3)))
+	}
+	if s.System != (model.System{}) {
+		miscLines = append(miscLines, renderSystemRates(s.System))
+	}
+	// zram swap is compressed, so show what it really costs in RAM
+	if z := s.Zram; z.Devices > 0 {
+		miscLines = append(miscLines, subtleStyle.Render(fmt.Sprintf("ZRAM: %.2f→%.2f GB (%.1fx) ram %.2f GB",
+			bytesToGiB(z.OrigBytes), bytesToGiB(z.ComprBytes), z.Ratio(), bytesToGiB(z.MemUsed))))
+	}
+	miscBlock := lipgloss.JoinVertical(lipgloss.Left, miscLines...)
 	miscCardStyle := cardStyle
-	if m.criticalSwap {
+	if m.criticalSwap || m.criticalStuck {
 		miscCardStyle = alertCardStyle
 	}
-	miscCard := miscCardStyle.Render(miscBlock)
-
-	row1 := lipgloss.JoinHorizontal(lipgloss.Top, cpuCard, memCard, miscCard)
-
-	// --- Row 2: Throughput & Hardware (NET, DISK, GPU, BATT) ---
+	return miscCardStyle.Render(miscBlock)
+}
 
+func (m *Model) renderNetCard(s model.Sample) string {
 	// Network - use enhanced sparklines with stats on wider terminals
 	var netRxSpark, netTxSpark string
 	if m.width >= 160 {
-		netRxSpark = renderSparklineWithStats(m.netRxHist, 30, successColor)
-		netTxSpark = renderSparklineWithStats(m.netTxHist, 30, "#0077FF")
+		netRxSpark = m.sparkStats(m.netRxHist, 30, successColor)
+		netTxSpark = m.sparkStats(m.netTxHist, 30, "#0077FF")
 	} else {
-		netRxSpark = renderSparklineAuto(m.netRxHist, 15, successColor)
-		netTxSpark = renderSparklineAuto(m.netTxHist, 15, "#0077FF")
+		netRxSpark = m.sparkAuto(m.netRxHist, 15, successColor)
+		netTxSpark = m.sparkAuto(m.netTxHist, 15, "#0077FF")
 	}
 	netBlock := lipgloss.JoinVertical(lipgloss.Left,
-		fmt.Sprintf("%s RX %5.1f Mb/s %s", valStyle.Foreground(lipgloss.Color(successColor)).Render("↓"), s.IO.NetRxMbps, netRxSpark),
-		fmt.Sprintf("%s TX %5.1f Mb/s %s", valStyle.Foreground(lipgloss.Color("#0077FF")).Render("↑"), s.IO.NetTxMbps, netTxSpark),
+		fmt.Sprintf("%s RX %s %s", valStyle.Foreground(lipgloss.Color(successColor)).Render("↓"), m.flash("net_rx", fmt.Sprintf("%8s", formatBitRate(s.IO.NetRxMbps))), netRxSpark),
+		fmt.Sprintf("%s TX %s %s", valStyle.Foreground(lipgloss.Color("#0077FF")).Render("↑"), m.flash("net_tx", fmt.Sprintf("%8s", formatBitRate(s.IO.NetTxMbps))), netTxSpark),
 	)
-	netCard := cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("NETWORK"), netBlock))
+	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("NETWORK"), netBlock))
+}
 
+func (m *Model) renderDiskCard(s model.Sample) string {
 	// Disk
-	diskRSpark := renderSparklineAuto(m.diskReadHist, 15, warningColor)
-	diskWSpark := renderSparklineAuto(m.diskWriteHist, 15, secondaryColor)
+	diskRSpark := m.sparkAuto(m.diskReadHist, 15, warningColor)
+	diskWSpark := m.sparkAuto(m.diskWriteHist, 15, secondaryColor)
 	topDevs := topDevices(s.IO.PerDevice, 3)
 	devLines := ""
 	for _, d := range topDevs {
-		devLines += fmt.Sprintf("%-6s R%5.1f W%5.1f MB/s\n", d.Name, d.ReadMBs, d.WriteMBs)
+		devLines += fmt.Sprintf("%-6s R%5s/s W%5s/s", d.Name, formatRate(d.ReadMBs*mib), formatRate(d.WriteMBs*mib))
+		if d.TempC > 0 {
+			// Drives throttle well below CPU limits; NVMe typically around 70°C
+			tempStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(coolColor))
+			if d.TempC >= 70 {
+				tempStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(criticalColor)).Bold(true)
+			} else if d.TempC >= 55 {
+				tempStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(warmColor))
+			}
+			devLines += " " + tempStyle.Render(fmt.Sprintf("%.0f°C", d.TempC))
+		}
+		devLines += "\n"
 	}
 	if devLines == "" {
 		devLines = subtleStyle.Render("no device stats")
 	}
 	diskBlock := lipgloss.JoinVertical(lipgloss.Left,
-		fmt.Sprintf("Total R %5.1f MB/s %s", s.IO.DiskReadMBs, diskRSpark),
-		fmt.Sprintf("Total W %5.1f MB/s %s", s.IO.DiskWriteMBs, diskWSpark),
+		fmt.Sprintf("Total R %s %s", m.flash("disk_r", fmt.Sprintf("%5s/s", formatRate(s.IO.DiskReadMBs*mib))), diskRSpark),
+		fmt.Sprintf("Total W %s %s", m.flash("disk_w", fmt.Sprintf("%5s/s", formatRate(s.IO.DiskWriteMBs*mib))), diskWSpark),
 		subtleStyle.Render("Top devices:"),
 		devLines,
 	)
-	diskCard := cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("DISK I/O"), diskBlock))
+	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("DISK I/O"), diskBlock))
+}
 
+// renderHardwareCard summarizes GPUs, batteries and temperatures.
+func (m *Model) renderHardwareCard(s model.Sample) string {
 	// GPU & Battery & Temperature Summary
 	var extraLines []string
 	if m.showGPU && len(s.GPUs) > 0 {
-		for _, g := range s.GPUs {
+		for gi, g := range s.GPUs {
 			// Color-coded temperature
 			tempStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(coolColor))
 			if g.TempC >= 85 {
@@ -747,13 +1911,42 @@ func (m *Model) renderDashboard(s model.Sample) string {
 			} else if g.TempC >= 50 {
 				tempStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(warmColor))
 			}
+			temp := ""
+			if g.TempC > 0 {
+				temp = tempStyle.Render(fmt.Sprintf("%2.0f°C", g.TempC))
+			}
+			// Integrated GPUs (intel_gpu_top) share system RAM and report
+			// media engine load and clock instead
+			memLine := fmt.Sprintf("   VRAM: %3.0f/%3.0f MB", g.MemUsedMB, g.MemTotalMB)
+			if g.MemTotalMB == 0 {
+				memLine = fmt.Sprintf("   video %3.0f%% · %4.0f MHz", g.VideoUtil, g.FreqMHz)
+			} else if g.FreqMHz > 0 && g.MemClockMHz > 0 {
+				memLine += subtleStyle.Render(fmt.Sprintf(" · %.0f/%.0f MHz", g.FreqMHz, g.MemClockMHz))
+			} else if g.FreqMHz > 0 {
+				memLine += subtleStyle.Render(fmt.Sprintf(" · %.0f MHz", g.FreqMHz))
+			}
 			extraLines = append(extraLines,
 				fmt.Sprintf("🎮 %s", truncate(g.Name, 12)),
 				fmt.Sprintf("   %s %s  %s",
 					renderMiniGauge(g.Util, 8),
 					lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%3.0f%%", g.Util)),
-					tempStyle.Render(fmt.Sprintf("%2.0f°C", g.TempC))),
-				fmt.Sprintf("   VRAM: %3.0f/%3.0f MB", g.MemUsedMB, g.MemTotalMB))
+					temp),
+				memLine,
+			)
+			if g.PowerW > 0 {
+				extraLines = append(extraLines, renderGPUPower(g))
+			}
+			extraLines = append(extraLines,
+				fmt.Sprintf("   %s %s %s %s",
+					subtleStyle.Render("util"), m.sparkPct(m.gpuUtilHist[gi], 8, successColor),
+					subtleStyle.Render("vram"), m.sparkPct(m.gpuMemHist[gi], 8, "#BD93F9")))
+		}
+		for i, gp := range s.GPUProcs {
+			if i >= 3 {
+				break
+			}
+			extraLines = append(extraLines,
+				subtleStyle.Render(fmt.Sprintf("   %-10s %6d %5.0fMB", truncate(gp.Name, 10), gp.PID, gp.MemUsedMB)))
 		}
 	}
 	if m.showBatt && s.Battery.Percent > 0 {
@@ -769,11 +1962,22 @@ func (m *Model) renderDashboard(s model.Sample) string {
 		if s.Battery.State == "Charging" {
 			battIcon = "⚡"
 		}
+		battInfo := s.Battery.State
+		if s.Battery.SecondsRemaining > 0 {
+			suffix := "remaining"
+			if s.Battery.State == "Charging" {
+				suffix = "to full"
+			}
+			battInfo += " · " + formatHM(s.Battery.SecondsRemaining) + " " + suffix
+		}
+		if s.Battery.PowerW > 0 {
+			battInfo += fmt.Sprintf(" · %.1fW", s.Battery.PowerW)
+		}
 		extraLines = append(extraLines,
 			fmt.Sprintf("%s %s %s",
 				battIcon,
 				battStyle.Render(fmt.Sprintf("%.0f%%", s.Battery.Percent)),
-				subtleStyle.Render(s.Battery.State)))
+				subtleStyle.Render(battInfo)))
 	}
 	// Show temperature summary if available
 	if m.showTemps && len(s.Temps) > 0 {
@@ -812,141 +2016,62 @@ func (m *Model) renderDashboard(s model.Sample) string {
 	if m.criticalTemp {
 		extraCardStyle = alertCardStyle
 	}
-	extraCard := extraCardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("HARDWARE"), extraContent))
-
-	row2 := lipgloss.JoinHorizontal(lipgloss.Top, netCard, diskCard, extraCard)
-
-	// --- Row 3: Main Content (Procs left, PerCore right) ---
+	return extraCardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("HARDWARE"), extraContent))
+}
 
-	// Process List (Left Column)
-	// Calculate available height conservatively to ensure everything fits on one screen
-	// header=3, row1=6, row2=9, footer=1, padding=3 -> ~22 lines used by other elements
-	// Cap the process area height to prevent overflow
-	availHeight := m.height - 22
-	if availHeight > 20 {
-		availHeight = 20 // Cap to prevent excessive vertical growth
-	}
-	if availHeight < 6 {
-		availHeight = 6
+// renderProcsCard is the process table with, on wide screens, the right
+// panel of leaders, throttled processes and CPU cores beside it.
+func (m *Model) renderProcsCard(s model.Sample, availHeight int) string {
+	// Use most of the horizontal space with many columns to minimize vertical height
+	// This keeps everything visible on one screen with scrolling for additional processes
+	filteredProcs := m.topRows(s)
+	totalProcs := len(filteredProcs)
+
+	// Scroll indicator with badge for count
+	scrollInfo := ""
+	procCountBadge := ""
+	if totalProcs > 0 {
+		visible := m.visibleTopCapacity()
+		endIdx := minInt(m.topOffset+visible, totalProcs)
+		procCountBadge = " " + badgeStyle.Render(formatInt(int64(totalProcs)))
+		scrollInfo = fmt.Sprintf(" [%d-%d of %s", m.topOffset+1, endIdx, formatInt(int64(totalProcs)))
+		if totalProcs > visible {
+			scrollInfo += ", j/k/PgUp/PgDn"
+		}
+		scrollInfo += "]"
 	}
-
-	row3 := func() string {
-		// Use most of the horizontal space with many columns to minimize vertical height
-		// This keeps everything visible on one screen with scrolling for additional processes
-		filteredProcs := m.sortAndFilter(s.Top)
-		totalProcs := len(filteredProcs)
-
-		// Scroll indicator with badge for count
-		scrollInfo := ""
-		procCountBadge := ""
-		if totalProcs > 0 {
-			visible := m.visibleTopCapacity()
-			endIdx := minInt(m.topOffset+visible, totalProcs)
-			procCountBadge = " " + badgeStyle.Render(fmt.Sprintf("%d", totalProcs))
-			scrollInfo = fmt.Sprintf(" [%d-%d of %d", m.topOffset+1, endIdx, totalProcs)
-			if totalProcs > visible {
-				scrollInfo += ", j/k/PgUp/PgDn"
+	procLabel := titleStyle.Render("TOP PROCESSES") + procCountBadge + subtleStyle.Render(scrollInfo)
+	if m.baselineByPID != nil {
+		procLabel += " " + badgeStyle.Background(lipgloss.Color(successColor)).Foreground(lipgloss.Color("#000000")).
+			Render(fmt.Sprintf("Δ vs %s", m.clock(m.baselineAt)))
+		if exited := m.exitedSinceBaseline(s.Top); len(exited) > 0 {
+			names := make([]string, 0, 3)
+			for i := 0; i < len(exited) && i < 3; i++ {
+				names = append(names, truncate(exited[i].Command, 14))
 			}
-			scrollInfo += "]"
+			procLabel += subtleStyle.Render(fmt.Sprintf(" exited %d: %s", len(exited), strings.Join(names, ", ")))
 		}
-		procLabel := titleStyle.Render("TOP PROCESSES") + procCountBadge + subtleStyle.Render(scrollInfo)
-
-		// Wide screens: have a right panel with IO/FD leaders, throttled, and cores
-		if m.width >= 160 {
-			rightWidth := minInt(44, m.width/4) // Wider right panel for IO/FD data
-			if rightWidth < 36 {
-				rightWidth = 36
-			}
-			procAreaWidth := m.width - rightWidth - 3
-
-			// Calculate columns based on process area width
-			cols := 1
-			if procAreaWidth >= 80 {
-				cols = 2
-			}
-			if procAreaWidth >= 120 {
-				cols = 3
-			}
-			if procAreaWidth >= 160 {
-				cols = 4
-			}
-
-			procTable := renderProcessColumns(filteredProcs, cols, availHeight, procAreaWidth-4, m.topOffset, primaryColor)
-			// Use focused style when a process is selected
-			procCardStyle := cardStyle
-			if m.selectedProc >= 0 {
-				procCardStyle = focusedCardStyle
-			}
-			procCard := procCardStyle.Width(procAreaWidth).Height(availHeight).
-				Render(lipgloss.JoinVertical(lipgloss.Left, procLabel, procTable))
-
-			// Right panel with IO/FD leaders, throttled processes, and CPU cores
-			var rightColContent string
-			if m.showIOPanels {
-				// Allocate space for IO TOP, FD TOP, THROTTLED, and CORES
-				ioHeight := maxInt(4, availHeight/4)
-				fdHeight := maxInt(3, availHeight/5)
-				thHeight := maxInt(3, availHeight/5)
-
-				ioTable := renderIOTable(m.topIO(s.Top), ioHeight, rightWidth-4)
-				fdTable := renderFDTable(m.topFD(s.Top), fdHeight, rightWidth-4)
-				throttledTable := renderProcessTableCompact(m.sortAndFilter(s.Throttled), thHeight, secondaryColor)
-				coreBlock := renderCoreGridCompact(m.perCoreHist, rightWidth-4)
-
-				// Use titleStyle for section headers and badgeStyle for throttled count
-				throttledCount := len(m.sortAndFilter(s.Throttled))
-				throttledBadge := ""
-				if throttledCount > 0 {
-					throttledBadge = " " + badgeStyle.Background(lipgloss.Color(secondaryColor)).Render(fmt.Sprintf("%d", throttledCount))
-				}
-
-				rightColContent = lipgloss.JoinVertical(lipgloss.Left,
-					titleStyle.Background(lipgloss.Color(warningColor)).Render("⚡ IO TOP"),
-					ioTable,
-					titleStyle.Background(lipgloss.Color(warningColor)).Render("📂 FD TOP"),
-					fdTable,
-					titleStyle.Background(lipgloss.Color(secondaryColor)).Render("🔻 THROTTLED")+throttledBadge,
-					throttledTable,
-					titleStyle.Render("CPU CORES"),
-					coreBlock,
-				)
-			} else {
-				// Without IO panels, show more throttled and cores
-				thHeight := maxInt(6, availHeight/3)
-				throttledProcs := m.sortAndFilter(s.Throttled)
-				throttledTable := renderProcessTableCompact(throttledProcs, thHeight, secondaryColor)
-				coreBlock := renderCoreGrid(m.perCoreHist, rightWidth-4)
-
-				// Badge for throttled count
-				throttledBadge := ""
-				if len(throttledProcs) > 0 {
-					throttledBadge = " " + badgeStyle.Background(lipgloss.Color(secondaryColor)).Render(fmt.Sprintf("%d", len(throttledProcs)))
-				}
-
-				rightColContent = lipgloss.JoinVertical(lipgloss.Left,
-					titleStyle.Background(lipgloss.Color(secondaryColor)).Render("🔻 THROTTLED")+throttledBadge,
-					throttledTable,
-					titleStyle.Render("CPU CORES"),
-					coreBlock,
-					subtleStyle.Render("(press i to show IO/FD panels)"),
-				)
-			}
+	}
 
-			rightCard := cardStyle.Width(rightWidth).Height(availHeight).Render(rightColContent)
-			return lipgloss.JoinHorizontal(lipgloss.Top, procCard, rightCard)
-		}
+	// Wide screens: have a right panel with IO/FD leaders, throttled, and cores
+	if m.width >= 160 {
+		rightWidth := m.rightPanelWidth()
+		procAreaWidth := m.width - rightWidth - 3
 
-		// Narrow screens: no right panel, full width for processes
-		procAreaWidth := m.width - 2
+		// Calculate columns based on process area width
 		cols := 1
-		if m.width >= 100 {
+		if procAreaWidth >= 80 {
 			cols = 2
 		}
-		if m.width >= 140 {
+		if procAreaWidth >= 120 {
 			cols = 3
 		}
+		if procAreaWidth >= 160 {
+			cols = 4
+		}
+		cols = m.fitProcColumns(cols, procAreaWidth-4)
 
-		procTable := renderProcessColumns(filteredProcs, cols, availHeight, procAreaWidth-4, m.topOffset, primaryColor)
+		procTable := m.withFilterSum(s, m.withPinned(renderProcessColumns(filteredProcs, cols, availHeight-m.pinnedLines()-m.filterSumLines(), procAreaWidth-4, m.topOffset, m.procTableOpts()), procAreaWidth-4), procAreaWidth-4)
 		// Use focused style when a process is selected
 		procCardStyle := cardStyle
 		if m.selectedProc >= 0 {
@@ -955,25 +2080,109 @@ func (m *Model) renderDashboard(s model.Sample) string {
 		procCard := procCardStyle.Width(procAreaWidth).Height(availHeight).
 			Render(lipgloss.JoinVertical(lipgloss.Left, procLabel, procTable))
 
-		return procCard
-	}()
+		// Right panel with IO/FD leaders, throttled processes, and CPU cores
+		var rightColContent string
+		if m.showIOPanels {
+			// Allocate space for the two side panels (IO TOP and FD TOP
+			// unless cycled with x), THROTTLED, and CORES
+			ioHeight := maxInt(4, availHeight/4)
+			fdHeight := maxInt(3, availHeight/5)
+			thHeight := maxInt(3, availHeight/5)
+
+			upper, lower := m.sidePanel(0), m.sidePanel(1)
+			ioTable := upper.render(m.sideTop(upper, s.Top), ioHeight, rightWidth-4)
+			fdTable := lower.render(m.sideTop(lower, s.Top), fdHeight, rightWidth-4)
+			throttledTable := renderProcessTableCompact(m.sortAndFilter(s.Throttled), thHeight, secondaryColor)
+			coreBlock := renderCoreGridCompact(m.perCoreHist, rightWidth-4, m.sparkPct)
+
+			// Use titleStyle for section headers and badgeStyle for throttled count
+			throttledCount := len(m.sortAndFilter(s.Throttled))
+			throttledBadge := ""
+			if throttledCount > 0 {
+				throttledBadge = " " + badgeStyle.Background(lipgloss.Color(secondaryColor)).Render(fmt.Sprintf("%d", throttledCount))
+			}
+
+			rightColContent = lipgloss.JoinVertical(lipgloss.Left,
+				titleStyle.Background(lipgloss.Color(warningColor)).Render(upper.title),
+				ioTable,
+				titleStyle.Background(lipgloss.Color(warningColor)).Render(lower.title),
+				fdTable,
+				titleStyle.Background(lipgloss.Color(secondaryColor)).Render("🔻 THROTTLED")+throttledBadge,
+				throttledTable,
+				titleStyle.Render("CPU CORES"),
+				coreBlock,
+			)
+		} else {
+			// Without IO panels, show more throttled and cores
+			thHeight := maxInt(6, availHeight/3)
+			throttledProcs := m.sortAndFilter(s.Throttled)
+			throttledTable := renderProcessTableCompact(throttledProcs, thHeight, secondaryColor)
+			coreBlock := renderCoreGrid(m.perCoreHist, rightWidth-4, m.sparkPct)
+
+			// Badge for throttled count
+			throttledBadge := ""
+			if len(throttledProcs) > 0 {
+				throttledBadge = " " + badgeStyle.Background(lipgloss.Color(secondaryColor)).Render(fmt.Sprintf("%d", len(throttledProcs)))
+			}
+
+			rightColContent = lipgloss.JoinVertical(lipgloss.Left,
+				titleStyle.Background(lipgloss.Color(secondaryColor)).Render("🔻 THROTTLED")+throttledBadge,
+				throttledTable,
+				titleStyle.Render("CPU CORES"),
+				coreBlock,
+				subtleStyle.Render("(press i to show IO/FD panels)"),
+			)
+		}
 
-	return lipgloss.JoinVertical(lipgloss.Left, row1, row2, row3)
+		rightCard := cardStyle.Width(rightWidth).Height(availHeight).Render(rightColContent)
+		return lipgloss.JoinHorizontal(lipgloss.Top, procCard, rightCard)
+	}
+
+	// Narrow screens: no right panel, full width for processes
+	procAreaWidth := m.width - 2
+	cols := 1
+	if m.width >= 100 {
+		cols = 2
+	}
+	if m.width >= 140 {
+		cols = 3
+	}
+	cols = m.fitProcColumns(cols, procAreaWidth-4)
+
+	procTable := m.withFilterSum(s, m.withPinned(renderProcessColumns(filteredProcs, cols, availHeight-m.pinnedLines()-m.filterSumLines(), procAreaWidth-4, m.topOffset, m.procTableOpts()), procAreaWidth-4), procAreaWidth-4)
+	// Use focused style when a process is selected
+	procCardStyle := cardStyle
+	if m.selectedProc >= 0 {
+		procCardStyle = focusedCardStyle
+	}
+	procCard := procCardStyle.Width(procAreaWidth).Height(availHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, procLabel, procTable))
+
+	return procCard
 }
 
 func (m *Model) renderAnalysis(s model.Sample) string {
 	availHeight := m.height - 4 // approximate header/padding
 
+	// Trend chart (bottom, full width) gets a third of the height
+	chartHeight := maxInt(5, availHeight/3)
+	availHeight -= chartHeight + 2 // card borders
+
 	// Hall of Shame (Left) - processes that have consumed the most CPU time
 	shameHeight := availHeight
-	shameRows := m.getHallOfShame(shameHeight - 4)
+	shameRows := m.getHallOfShame(shameHeight - 5)
 	shameTable := renderSimpleTable([]string{"COMMAND", "CPU-SEC"}, shameRows, 25, primaryColor)
 	shameBadge := ""
 	if len(shameRows) > 0 {
 		shameBadge = " " + badgeStyle.Render(fmt.Sprintf("%d", len(shameRows)))
 	}
+	shameMode := "session, L: lifetime"
+	if m.shameLifetime {
+		shameMode = "lifetime, L: session"
+	}
 	shameCard := cardStyle.Width(40).Height(shameHeight).Render(lipgloss.JoinVertical(lipgloss.Left,
 		titleStyle.Render("🏆 HALL OF SHAME")+shameBadge,
+		subtleStyle.Render(shameMode),
 		shameTable))
 
 	// Frequent Flyers (Right) - processes that have been throttled most often
@@ -987,10 +2196,203 @@ func (m *Model) renderAnalysis(s model.Sample) string {
 		titleStyle.Background(lipgloss.Color(secondaryColor)).Render("✈️ FREQUENT FLYERS")+freqBadge,
 		freqTable))
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, shameCard, freqCard)
+	balanceWidth := maxInt(40, m.width-86)
+	balance := lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Background(lipgloss.Color(coolColor)).Render("⚖ CORE BALANCE"),
+		m.renderCoreBalance(balanceWidth-4))
+	pctl := m.renderPercentiles(balanceWidth - 4)
+	ph := lipgloss.Height(pctl)
+	pctlCard := cardStyle.Width(balanceWidth).Height(ph).Render(pctl)
+	var balanceCard string
+	if m.watchdog != nil {
+		// The watchdog log takes what core balance and percentiles leave of
+		// the right column
+		bh := lipgloss.Height(balance)
+		wdHeight := maxInt(3, shameHeight-bh-ph-4)
+		balanceCard = lipgloss.JoinVertical(lipgloss.Left,
+			cardStyle.Width(balanceWidth).Height(bh).Render(balance),
+			pctlCard,
+			cardStyle.Width(balanceWidth).Height(wdHeight).Render(m.renderWatchdogLog(balanceWidth-4, wdHeight)))
+	} else {
+		balanceCard = lipgloss.JoinVertical(lipgloss.Left,
+			cardStyle.Width(balanceWidth).Height(maxInt(3, shameHeight-ph-2)).Render(balance),
+			pctlCard)
+	}
+
+	chartCard := cardStyle.Width(m.width - 3).Height(chartHeight).
+		Render(m.renderTrendChart(m.width-7, chartHeight))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, shameCard, freqCard, balanceCard),
+		chartCard)
+}
+
+// renderPercentiles tabulates p50/p95/p99 and max of CPU, memory and network
+// over the retained samples, to show how bad it gets rather than the average.
+// The max column is dropped when width is too narrow for it.
+func (m *Model) renderPercentiles(width int) string {
+	samples := m.retained.Since(0)
+	title := titleStyle.Background(lipgloss.Color(accentColor)).Render("📊 PERCENTILES")
+	if len(samples) < 2 {
+		return title + "\n" + subtleStyle.Render("Collecting samples...")
+	}
+	span := samples[len(samples)-1].Timestamp.Sub(samples[0].Timestamp).Round(time.Second)
+	title += "\n" + subtleStyle.Render(fmt.Sprintf("last %s · %d samples", span, len(samples)))
+
+	series := func(f func(model.Sample) float64) []float64 {
+		vals := make([]float64, len(samples))
+		for i, s := range samples {
+			vals[i] = f(s)
+		}
+		sort.Float64s(vals)
+		return vals
+	}
+	pctFmt := func(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+	rows := []struct {
+		label  string
+		vals   []float64
+		format func(float64) string
+	}{
+		{"CPU", series(func(s model.Sample) float64 { return s.CPU.Total }), pctFmt},
+		{"MEM", series(func(s model.Sample) float64 { return pct(s.Memory.UsedBytes, s.Memory.TotalBytes) }), pctFmt},
+		{"NET ↓", series(func(s model.Sample) float64 { return s.IO.NetRxMbps }), formatBitRate},
+		{"NET ↑", series(func(s model.Sample) float64 { return s.IO.NetTxMbps }), formatBitRate},
+	}
+
+	cols := []float64{50, 95, 99, 100}
+	if width < 46 {
+		cols = cols[:3]
+	}
+	header := fmt.Sprintf("%-6s", "")
+	for _, p := range cols {
+		name := fmt.Sprintf("p%.0f", p)
+		if p == 100 {
+			name = "max"
+		}
+		header += fmt.Sprintf(" %9s", name)
+	}
+	lines := []string{title, subtleStyle.Render(header)}
+	for _, r := range rows {
+		line := fmt.Sprintf("%-6s", r.label)
+		for _, p := range cols {
+			line += fmt.Sprintf(" %9s", r.format(percentile(r.vals, p)))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted, non-empty vals.
+func percentile(sorted []float64, p float64) float64 {
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[maxInt(0, minInt(i, len(sorted)-1))]
+}
+
+// renderWatchdogLog lists the watchdog's recent actions, newest first.
+func (m *Model) renderWatchdogLog(width, height int) string {
+	wd := m.watchdog
+	title := titleStyle.Background(lipgloss.Color(warningColor)).Render("🛡 WATCHDOG") +
+		subtleStyle.Render(fmt.Sprintf(" %s · ≥%.0f%% for %d samples → nice %d", wd.Mode(), wd.CPU, wd.Samples, wd.Nice))
+	lines := []string{title}
+	if len(m.watchdogLog) == 0 {
+		lines = append(lines, subtleStyle.Render("No CPU hogs caught yet"))
+	}
+	for i := len(m.watchdogLog) - 1; i >= 0 && len(lines) < height; i-- {
+		a := m.watchdogLog[i]
+		style := rowStyle
+		if a.Err != "" {
+			style = lipgloss.NewStyle().Foreground(lipgloss.Color(hotColor))
+		}
+		lines = append(lines, truncate(m.clock(a.Timestamp)+" "+a.String(), width))
+		lines[len(lines)-1] = style.Render(lines[len(lines)-1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderCoreBalance renders a histogram of the latest per-core utilization
+// plus spread statistics, to tell one pegged core from evenly spread load.
+func (m *Model) renderCoreBalance(width int) string {
+	var latest []float64
+	for i := 0; i < len(m.perCoreHist); i++ {
+		if h := m.perCoreHist[i]; len(h) > 0 {
+			latest = append(latest, h[len(h)-1])
+		}
+	}
+	if len(latest) == 0 {
+		return subtleStyle.Render("Waiting for per-core data...")
+	}
+
+	var buckets [10]int
+	minV, maxV, sum := 100.0, 0.0, 0.0
+	maxCore := 0
+	for i, v := range latest {
+		buckets[minInt(int(v/10), 9)]++
+		sum += v
+		minV = math.Min(minV, v)
+		if v > maxV {
+			maxV, maxCore = v, i
+		}
+	}
+	mean := sum / float64(len(latest))
+	variance := 0.0
+	for _, v := range latest {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(latest)))
+	// 100 = every core equally busy; low when one core carries the load
+	balance := 100.0
+	if maxV >= 1 {
+		balance = mean / maxV * 100
+	}
+
+	var b strings.Builder
+	barWidth := minInt(40, maxInt(5, width-16))
+	peak := 1
+	for _, n := range buckets {
+		peak = maxInt(peak, n)
+	}
+	for i := len(buckets) - 1; i >= 0; i-- {
+		n := buckets[i]
+		bar := strings.Repeat("█", n*barWidth/peak)
+		if n > 0 && bar == "" {
+			bar = "▏"
+		}
+		color := successColor
+		if i >= 9 {
+			color = criticalColor
+		} else if i >= 7 {
+			color = warningColor
+		}
+		b.WriteString(subtleStyle.Render(fmt.Sprintf("%-8s ", fmt.Sprintf("%d-%d%%", i*10, i*10+10))))
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(bar))
+		b.WriteString(subtleStyle.Render(fmt.Sprintf(" %d", n)) + "\n")
+	}
+
+	balanceStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(successColor)).Bold(true)
+	if balance < 40 && maxV > 50 {
+		balanceStyle = criticalStyle
+	} else if balance < 70 && maxV > 50 {
+		balanceStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Bold(true)
+	}
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("%d cores  min %.0f%%  max %.0f%% (core %d)\n", len(latest), minV, maxV, maxCore))
+	b.WriteString(fmt.Sprintf("mean %.1f%%  stddev %.1f\n", mean, stddev))
+	b.WriteString("balance " + balanceStyle.Render(fmt.Sprintf("%.0f/100", balance)))
+	return b.String()
 }
 
 // Helpers for Analysis data
+// lifetimeEntry is the last seen utime+stime of a PID.
+type lifetimeEntry struct {
+	command string
+	seconds float64
+	seen    time.Time // sample the PID was last in
+}
+
+// lifetimeStale is how long a remote PID may be missing from the sample
+// before it is taken as exited.
+const lifetimeStale = 10 * time.Minute
+
 func (m *Model) getHallOfShame(limit int) []string {
 	if limit < 1 {
 		limit = 1
@@ -999,17 +2401,30 @@ func (m *Model) getHallOfShame(limit int) []string {
 		k string
 		v float64
 	}
+	// Both modes rank core-seconds per command
+	totals := make(map[string]float64)
+	if m.shameLifetime {
+		for k, v := range m.lifetimeGone {
+			totals[k] = v
+		}
+		for _, e := range m.lifetimeCPU {
+			totals[e.command] += e.seconds
+		}
+	} else {
+		for k, v := range m.cumulativeCPU {
+			// Divide by 100 to get "Core-Seconds"
+			totals[k] = v / 100.0
+		}
+	}
 	var ss []kv
-	for k, v := range m.cumulativeCPU {
+	for k, v := range totals {
 		ss = append(ss, kv{k, v})
 	}
 	sort.Slice(ss, func(i, j int) bool { return ss[i].v > ss[j].v })
 
 	var rows []string
 	for i := 0; i < limit && i < len(ss); i++ {
-		// Divide by 100 to get "Core-Seconds"
-		val := ss[i].v / 100.0
-		rows = append(rows, fmt.Sprintf("%-18s %6.1f", truncate(ss[i].k, 18), val))
+		rows = append(rows, fmt.Sprintf("%-18s %6.1f", truncate(ss[i].k, 18), ss[i].v))
 	}
 	return rows
 }
@@ -1035,34 +2450,100 @@ func (m *Model) getFrequentFlyers(limit int) []string {
 	return rows
 }
 
-func (m *Model) topIO(procs []model.Process) []model.Process {
-	sorted := append([]model.Process{}, procs...)
-	sort.Slice(sorted, func(i, j int) bool {
-		return (sorted[i].ReadKBs + sorted[i].WriteKBs) > (sorted[j].ReadKBs + sorted[j].WriteKBs)
-	})
-	if len(sorted) > 8 {
-		sorted = sorted[:8]
+// exitedSinceBaseline lists baseline processes that no longer exist.
+func (m *Model) exitedSinceBaseline(cur []model.Process) []model.Process {
+	alive := make(map[int]bool, len(cur))
+	for _, p := range cur {
+		alive[p.PID] = true
 	}
-	return sorted
-}
-
-func (m *Model) topFD(procs []model.Process) []model.Process {
-	sorted := append([]model.Process{}, procs...)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].FDCount > sorted[j].FDCount
-	})
-	if len(sorted) > 8 {
-		sorted = sorted[:8]
+	var exited []model.Process
+	if m.remote != nil {
+		return nil // can't tell exited from merely out of the top list
 	}
-	return sorted
+	for pid, p := range m.baselineByPID {
+		if alive[pid] {
+			continue
+		}
+		// Dropping out of the sampled top list is not the same as exiting
+		if sampler.Alive(pid) {
+			continue
+		}
+		exited = append(exited, p)
+	}
+	sort.Slice(exited, func(i, j int) bool { return exited[i].CPU > exited[j].CPU })
+	return exited
 }
 
-func (m *Model) renderHelp() string {
-	// Build a visually appealing help screen using global styles
-	helpTitleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(primaryColor)).
-		Bold(true)
-	keyStyle := lipgloss.NewStyle().
+// OOM forecast: memTrend only trusts a fit over at least oomMinPoints
+// samples that climbed oomMinRise points and explains most of the variance,
+// and only reports an exhaustion time within oomHorizon.
+const (
+	oomMinPoints = 10
+	oomMinRise   = 1.0
+	oomMinR2     = 0.8
+	oomHorizon   = 15 * time.Minute
+)
+
+// memTrend fits a least-squares line to memHist against sample time and,
+// when memory is clearly climbing, estimates how long until it is full.
+// perMin is the slope in percentage points per minute.
+func (m *Model) memTrend() (eta time.Duration, perMin float64, ok bool) {
+	n := minInt(len(m.memHist), len(m.timeHist))
+	if n < oomMinPoints {
+		return 0, 0, false
+	}
+	ys := m.memHist[len(m.memHist)-n:]
+	ts := m.timeHist[len(m.timeHist)-n:]
+	xs := make([]float64, n)
+	for i, t := range ts {
+		xs[i] = t.Sub(ts[0]).Seconds()
+	}
+	r, ok := correlation(xs, ys)
+	if !ok || r*r < oomMinR2 {
+		return 0, 0, false
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+	var cov, varX float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	slope := cov / varX // points per second
+	if slope <= 0 || slope*xs[n-1] < oomMinRise {
+		return 0, 0, false
+	}
+	eta = time.Duration((100 - ys[n-1]) / slope * float64(time.Second))
+	if eta > oomHorizon {
+		return 0, 0, false
+	}
+	return eta, slope * 60, true
+}
+
+// likelyOOMVictim returns the process with the highest oom_score.
+func likelyOOMVictim(procs []model.Process) (model.Process, bool) {
+	var victim model.Process
+	found := false
+	for _, p := range procs {
+		if p.OOMScore > 0 && (!found || p.OOMScore > victim.OOMScore) {
+			victim = p
+			found = true
+		}
+	}
+	return victim, found
+}
+
+func (m *Model) renderHelp() string {
+	// Build a visually appealing help screen using global styles
+	helpTitleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(primaryColor)).
+		Bold(true)
+	keyStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(warningColor)).
 		Bold(true)
 	descStyle := lipgloss.NewStyle().
@@ -1097,33 +2578,64 @@ func (m *Model) renderHelp() string {
 	b.WriteString(helpTitleStyle.Render(borderBottom) + "\n\n")
 
 	b.WriteString(sectionStyle.Render("⌨️  NAVIGATION") + "\n")
-	b.WriteString(keyStyle.Render("  q/Ctrl+C") + descStyle.Render("      Quit application") + "\n")
-	b.WriteString(keyStyle.Render("  Tab/1-3") + descStyle.Render("       Switch tabs (Dashboard/Analysis/System)") + "\n")
+	b.WriteString(keyStyle.Render("  q/Q/Ctrl+C") + descStyle.Render("    Quit (q asks first with --confirm-quit)") + "\n")
+	b.WriteString(keyStyle.Render("  Tab/1-4") + descStyle.Render("       Switch tabs (Dashboard/Analysis/System/Throughput)") + "\n")
 	b.WriteString(keyStyle.Render("  j/k ↑/↓") + descStyle.Render("       Scroll process list / move selection") + "\n")
 	b.WriteString(keyStyle.Render("  PgUp/PgDn") + descStyle.Render("     Page through process list") + "\n")
 	b.WriteString(keyStyle.Render("  Home/End") + descStyle.Render("      Jump to start/end of list") + "\n")
-	b.WriteString(keyStyle.Render("  Enter") + descStyle.Render("         Show process details modal") + "\n")
+	b.WriteString(keyStyle.Render("  Enter") + descStyle.Render("         Show process details (j/k step, v env, y copy, i/n ionice/renice)") + "\n")
 	b.WriteString(keyStyle.Render("  Esc") + descStyle.Render("           Clear selection/filter, close modal") + "\n")
 
 	b.WriteString(sectionStyle.Render("🔍 FILTERING & SORTING") + "\n")
-	b.WriteString(keyStyle.Render("  /") + descStyle.Render("             Start filter input (Enter=apply, Esc=cancel)") + "\n")
-	b.WriteString(keyStyle.Render("  s") + descStyle.Render("             Cycle sort: CPU → MEM → IO → FD") + "\n")
+	b.WriteString(keyStyle.Render("  /") + descStyle.Render("             Start filter input (Enter=apply, Esc=cancel, ↑/↓=history)") + "\n")
+	b.WriteString(keyStyle.Render("  : / ;") + descStyle.Render("         Search: select the next match without hiding rows / repeat") + "\n")
+	b.WriteString(keyStyle.Render("  C") + descStyle.Render("             Choose process table columns (saved to config)") + "\n")
+	b.WriteString(keyStyle.Render("  % / M") + descStyle.Render("         Cycle minimum CPU / MEM threshold") + "\n")
+	b.WriteString(keyStyle.Render("  H") + descStyle.Render("             Toggle highlight mode (keep all rows, mark matches)") + "\n")
+	b.WriteString(keyStyle.Render("  s") + descStyle.Render("             Cycle sort: CPU → MEM → IO → FD → CONN → OOM → ΔMEM → ΔFD → MAJF") + "\n")
+	b.WriteString(keyStyle.Render("  S / r") + descStyle.Render("         Cycle secondary (tiebreak) sort / reverse direction") + "\n")
 
 	b.WriteString(sectionStyle.Render("🎛️  PANEL TOGGLES") + "\n")
 	b.WriteString(keyStyle.Render("  g") + descStyle.Render("             Toggle GPU panel") + "\n")
 	b.WriteString(keyStyle.Render("  b") + descStyle.Render("             Toggle Battery panel") + "\n")
 	b.WriteString(keyStyle.Render("  i") + descStyle.Render("             Toggle IO/FD panels") + "\n")
+	b.WriteString(keyStyle.Render("  x") + descStyle.Render("             Cycle the lower side panel (minimal: the focused one) through io, fd, threads, conn, dmem, majflt, blkio") + "\n")
 	b.WriteString(keyStyle.Render("  t") + descStyle.Render("             Toggle Temperature panel") + "\n")
 	b.WriteString(keyStyle.Render("  n") + descStyle.Render("             Toggle Inotify panel") + "\n")
+	b.WriteString(keyStyle.Render("  u") + descStyle.Render("             Cycle cgroup panel: cgroups → systemd units → containers") + "\n")
+	b.WriteString(keyStyle.Render("  w") + descStyle.Render("             Toggle socket state tally (System tab)") + "\n")
 	b.WriteString(keyStyle.Render("  c") + descStyle.Render("             Toggle Cgroups panel") + "\n")
 
 	b.WriteString(sectionStyle.Render("⚙️  OTHER CONTROLS") + "\n")
 	b.WriteString(keyStyle.Render("  f") + descStyle.Render("             Freeze/unfreeze updates") + "\n")
-	b.WriteString(keyStyle.Render("  m") + descStyle.Render("             Toggle mouse support") + "\n")
+	b.WriteString(keyStyle.Render("  .") + descStyle.Render("             While frozen, step one sample (freezes first)") + "\n")
+	b.WriteString(keyStyle.Render("  p") + descStyle.Render("             Pin/unpin selected process above the table") + "\n")
+	b.WriteString(keyStyle.Render("  a") + descStyle.Render("             Mute/unmute the critical alert bell") + "\n")
+	b.WriteString(keyStyle.Render("  z") + descStyle.Render("             Per-process CPU: per-core sum ↔ share of machine") + "\n")
+	b.WriteString(keyStyle.Render("  F") + descStyle.Render("             Minimal mode: one maximized panel (tab cycles)") + "\n")
+	b.WriteString(keyStyle.Render("  G") + descStyle.Render("             Cycle Analysis trend chart: CPU/MEM/NET/DISK") + "\n")
+	b.WriteString(keyStyle.Render("  V") + descStyle.Render("             Color sparklines by value (green→red)") + "\n")
+	b.WriteString(keyStyle.Render("  L") + descStyle.Render("             Hall of Shame: session ↔ lifetime CPU time") + "\n")
+	b.WriteString(keyStyle.Render("  A") + descStyle.Render("             Group processes by command (Enter expands)") + "\n")
+	b.WriteString(keyStyle.Render("  N") + descStyle.Render("             Cycle CMD display: cmdline → comm → exe") + "\n")
+	b.WriteString(keyStyle.Render("  T") + descStyle.Render("             Show/hide kernel threads") + "\n")
+	b.WriteString(keyStyle.Render("  Z") + descStyle.Render("             Cycle states shown: all → active → R/D only") + "\n")
+	b.WriteString(keyStyle.Render("  [ / ]") + descStyle.Render("         Capture baseline & show deltas / exit diff mode") + "\n")
+	b.WriteString(keyStyle.Render("  +/-") + descStyle.Render("           Faster/slower refresh (250ms-10s)") + "\n")
+	b.WriteString(keyStyle.Render("  d") + descStyle.Render("             Cycle refresh presets: 250ms → 500ms → 1s → 2s → 5s") + "\n")
+	b.WriteString(keyStyle.Render("  m") + descStyle.Render("             Toggle mouse support (drag the right panel border to resize, hover gauges and sparklines for stats)") + "\n")
 	b.WriteString(keyStyle.Render("  I") + descStyle.Render("             Show ionice tip for top process") + "\n")
 	b.WriteString(keyStyle.Render("  o") + descStyle.Render("             Toggle JSON output (SRPS_SYSMONI_JSON_FILE)") + "\n")
+	b.WriteString(keyStyle.Render("  X") + descStyle.Render("             SIGTERM all processes matching filter (confirm)") + "\n")
+	b.WriteString(keyStyle.Render("  e") + descStyle.Render("             Export session history to CSV") + "\n")
+	b.WriteString(keyStyle.Render("  P") + descStyle.Render("             Save the current screen as text and SVG") + "\n")
 	b.WriteString(keyStyle.Render("  ?/h") + descStyle.Render("           Toggle this help") + "\n")
 
+	if len(m.cfg.Keys) > 0 {
+		b.WriteString(sectionStyle.Render("🔁 REMAPPED (config key_<action>)") + "\n")
+		b.WriteString(descStyle.Render("  "+remappedKeys(m.cfg.Keys)) + "\n")
+	}
+
 	b.WriteString(sectionStyle.Render("🖱️  MOUSE SUPPORT") + "\n")
 	b.WriteString(descStyle.Render("  Click on processes to select, scroll wheel to navigate") + "\n")
 
@@ -1171,6 +2683,103 @@ func renderSimpleTable(headers []string, rows []string, width int, color string)
 
 // renderGauge is a convenience wrapper for renderGaugeEnhanced with defaults
 // Use this for simple gauges where gradient coloring is desired
+// renderCPUWaits is the line under the CPU gauge. High iowait while the CPUs
+// are otherwise idle means processes are stuck on storage, not compute; any
+// steal means the hypervisor is withholding CPU this VM asked for.
+func renderCPUWaits(c model.CPU) string {
+	level := func(v, warn, crit float64) lipgloss.Style {
+		switch {
+		case v >= crit:
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(criticalColor)).Bold(true)
+		case v >= warn:
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Bold(true)
+		}
+		return valStyle
+	}
+	ioStyle := level(c.IOWait, 15, 40)
+	line := subtleStyle.Render("iowait ") + ioStyle.Render(fmt.Sprintf("%.1f%%", c.IOWait))
+	if c.Steal >= 0.1 {
+		line += subtleStyle.Render(" steal ") + level(c.Steal, 0.1, 10).Render(fmt.Sprintf("%.1f%%", c.Steal))
+	}
+	if c.Guest >= 0.1 {
+		line += subtleStyle.Render(fmt.Sprintf(" guest %.1f%%", c.Guest))
+	}
+	if c.IOWait >= 15 && c.Total < 50 {
+		line += " " + ioStyle.Render("⏳ disk-bound, not CPU")
+	} else if c.Steal >= 10 {
+		line += " " + level(c.Steal, 0, 10).Render("⚠ hypervisor steal")
+	}
+	return line
+}
+
+// renderTasks shows process/thread counts and the run queue, top-style. More
+// runnable threads than cores means work is queueing for CPU; any D-state
+// thread is waiting on IO it cannot be interrupted from.
+func renderTasks(t model.Tasks, cores int) string {
+	runStyle := subtleStyle
+	if t.Running > maxInt(1, cores) {
+		runStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Bold(true)
+	}
+	blockedStyle := subtleStyle
+	if t.Blocked > 0 {
+		blockedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(hotColor))
+	}
+	return miniGaugeStyle.Render("TASKS: ") +
+		subtleStyle.Render(fmt.Sprintf("%s procs, %s thr, ", formatInt(int64(t.Processes)), formatInt(int64(t.Threads)))) +
+		runStyle.Render(fmt.Sprintf("%s run", formatInt(int64(t.Running)))) + subtleStyle.Render(", ") +
+		blockedStyle.Render(fmt.Sprintf("%d D", t.Blocked))
+}
+
+// renderGPUPower shows a GPU's power draw against its limit. Drawing at the
+// limit means the card is power-capped: clocks drop rather than temps rise.
+func renderGPUPower(g model.GPU) string {
+	if g.PowerLimitW <= 0 {
+		return fmt.Sprintf("   PWR %3.0fW", g.PowerW)
+	}
+	p := g.PowerW / g.PowerLimitW * 100
+	line := fmt.Sprintf("   PWR %s %3.0f/%.0fW", renderMiniGauge(p, 8), g.PowerW, g.PowerLimitW)
+	if p >= 95 {
+		line += " " + lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Bold(true).Render("CAPPED")
+	}
+	return line
+}
+
+// stuckNames lists up to n D-state processes as "name (pid)", noting how
+// many more there are.
+func stuckNames(procs []model.Process, n int) string {
+	names := make([]string, 0, n)
+	for i := 0; i < len(procs) && i < n; i++ {
+		names = append(names, fmt.Sprintf("%s (%d)", truncate(procs[i].Command, 20), procs[i].PID))
+	}
+	if more := len(procs) - len(names); more > 0 {
+		names = append(names, fmt.Sprintf("+%d more", more))
+	}
+	return strings.Join(names, ", ")
+}
+
+// renderSwapIO shows pages swapped in and out per second. Swap that is full
+// but idle is harmless; traffic, and above all both directions at once
+// (pages evicted and faulted straight back), is thrashing.
+func renderSwapIO(mem model.Memory) string {
+	style := subtleStyle
+	switch {
+	case mem.SwapInBps > 0 && mem.SwapOutBps > 0:
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color(hotColor)).Bold(true)
+	case mem.SwapInBps > 0 || mem.SwapOutBps > 0:
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor))
+	}
+	return miniGaugeStyle.Render("SWAP IO: ") +
+		style.Render(fmt.Sprintf("in %s/s, out %s/s", formatRate(mem.SwapInBps), formatRate(mem.SwapOutBps)))
+}
+
+// renderSystemRates shows the kernel-wide context switch, interrupt and fork
+// rates; a sudden jump in either of the first two often precedes trouble.
+func renderSystemRates(sys model.System) string {
+	return miniGaugeStyle.Render("KERNEL: ") +
+		subtleStyle.Render(fmt.Sprintf("%s ctxsw/s, %s intr/s, %s forks/s",
+			formatCount(sys.CtxSwitches), formatCount(sys.Interrupts), formatCount(sys.Forks)))
+}
+
 func renderGauge(label string, pct float64) string {
 	return renderGaugeEnhanced(label, pct, primaryColor, true)
 }
@@ -1353,8 +2962,89 @@ func renderSparklinePct(values []float64, width int, color string) string {
 	return style.Render(b.String())
 }
 
+// renderSparklineGradient is the value-colored variant of the sparklines: each
+// bar takes interpolateColor of its height, so spikes stand out red. top is
+// the full-scale value (100 for percentages); 0 scales to the largest value.
+func renderSparklineGradient(values []float64, width int, top float64) string {
+	if len(values) == 0 {
+		return strings.Repeat(" ", width)
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if top <= 0 {
+		for _, v := range values {
+			top = math.Max(top, v)
+		}
+		if top == 0 {
+			top = 1
+		}
+	}
+
+	chars := []rune(" ▂▃▄▅▆▇█")
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		ratio := math.Min(math.Max(v/top, 0), 1)
+		bar := string(chars[int(ratio*float64(len(chars)-1))])
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(interpolateColor(ratio * 100))).Render(bar))
+	}
+	return b.String()
+}
+
+// sparkFunc renders a sparkline in a metric's flat color or, with V, by value.
+type sparkFunc func(values []float64, width int, color string) string
+
+// sparkPct renders a 0-100 series.
+func (m *Model) sparkPct(values []float64, width int, color string) string {
+	if m.sparkGradient {
+		return renderSparklineGradient(values, width, 100)
+	}
+	return renderSparklinePct(values, width, color)
+}
+
+// sparkAuto renders a series scaled to its own peak.
+func (m *Model) sparkAuto(values []float64, width int, color string) string {
+	if m.sparkGradient {
+		return renderSparklineGradient(values, width, 0)
+	}
+	return renderSparklineAuto(values, width, color)
+}
+
+// sparkStats renders a peak-scaled series followed by its max/min/avg.
+func (m *Model) sparkStats(values []float64, width int, color string) string {
+	if m.sparkGradient && len(values) > 0 {
+		return renderSparklineGradient(values, maxInt(5, width-16), 0) + sparkStatsLabel(values)
+	}
+	return renderSparklineWithStats(values, width, color)
+}
+
+// procTableOpts carries the per-render options for the main process table.
+type procTableOpts struct {
+	columns        []procColumn
+	highlightColor string
+	baseline       map[int]model.Process // non-nil switches metrics to deltas
+	match          string                // substring to highlight in CMD (highlight mode)
+	maxCmd         int                   // CMD column cap from -cmd-width; 0 = fill
+	guard          *watchdog.Guard       // protected rows get a lock in CMD
+}
+
+func (m *Model) procTableOpts() procTableOpts {
+	opts := procTableOpts{
+		columns:        m.procColumns(),
+		highlightColor: primaryColor,
+		baseline:       m.baselineByPID,
+		maxCmd:         m.cfg.CmdWidth,
+		guard:          m.guard,
+	}
+	if m.highlightMode {
+		opts.match = m.activePattern()
+	}
+	return opts
+}
+
 // renderProcessColumns splits the process table into multiple narrow columns to avoid tall lists.
-func renderProcessColumns(procs []model.Process, columns, height, totalWidth int, offset int, highlightColor string) string {
+func renderProcessColumns(procs []model.Process, columns, height, totalWidth int, offset int, opts procTableOpts) string {
 	if columns < 1 {
 		columns = 1
 	}
@@ -1382,7 +3072,14 @@ func renderProcessColumns(procs []model.Process, columns, height, totalWidth int
 	if colWidth*columns > totalWidth {
 		colWidth = maxInt(16, totalWidth/columns)
 	}
-	cmdWidth := colWidth - 32 // leave room for metrics
+	fixed := fixedColumnsWidth(opts.columns)
+	if opts.baseline != nil {
+		fixed = 40 // PID CPU and the four delta columns
+	}
+	cmdWidth := colWidth - fixed - 1 // leave room for metrics and the column gap
+	if opts.maxCmd > 0 && cmdWidth > opts.maxCmd {
+		cmdWidth = opts.maxCmd
+	}
 	if cmdWidth < 8 {
 		cmdWidth = 8
 	}
@@ -1395,39 +3092,84 @@ func renderProcessColumns(procs []model.Process, columns, height, totalWidth int
 			break
 		}
 		end := minInt(start+maxRows, limit)
-		col := renderProcessColumn(procs[start:end], maxRows, cmdWidth, highlightColor)
+		col := renderProcessColumn(procs[start:end], maxRows, cmdWidth, opts)
 		cols = append(cols, lipgloss.NewStyle().Width(colWidth).Render(col))
 	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, cols...)
 }
 
-func renderProcessColumn(procs []model.Process, maxRows int, cmdWidth int, highlightColor string) string {
+func renderProcessColumn(procs []model.Process, maxRows int, cmdWidth int, opts procTableOpts) string {
+	baseline := opts.baseline
 	var b strings.Builder
-	header := fmt.Sprintf("%-*s %5s %3s %5s %5s %5s %5s %4s", cmdWidth, "CMD", "PID", "NI", "CPU", "MEM", "Rk", "Wk", "FD")
+	header := formatProcRow(opts.columns, nil, cmdWidth)
+	if baseline != nil {
+		header = fmt.Sprintf("%-*s %5s %5s %6s %6s %5s %7s", cmdWidth, "CMD", "PID", "CPU", "ΔCPU", "ΔMEM", "ΔFD", "ΔIOk")
+	}
 	b.WriteString(tableHeaderStyle.Render(header) + "\n")
 
 	for i, p := range procs {
 		if i >= maxRows {
 			break
 		}
-		cmd := truncate(p.Command, cmdWidth)
-		line := fmt.Sprintf("%-*s %5d %3d %5.1f %5.1f %5.0f %5.0f %4d", cmdWidth, cmd, p.PID, p.Nice, p.CPU, p.Memory, p.ReadKBs, p.WriteKBs, p.FDCount)
+		w := cmdWidth
+		if opts.guard.Protects(p) {
+			// The lock is two cells but one rune; pad to one rune less
+			p.Command = "🔒" + p.Command
+			w--
+		}
+		cmd := truncate(p.Command, w)
+		line := formatProcRow(opts.columns, &p, w)
+		if !hasCmdColumn(opts.columns) {
+			cmd = "" // nothing to highlight
+		}
+		isNew := false
+		if baseline != nil {
+			if base, ok := baseline[p.PID]; ok {
+				line = fmt.Sprintf("%-*s %5d %5.1f %+6.1f %+6.1f %+5d %+7.0f", w, cmd, p.PID, p.CPU,
+					p.CPU-base.CPU, p.Memory-base.Memory, p.FDCount-base.FDCount,
+					(p.ReadKBs+p.WriteKBs)-(base.ReadKBs+base.WriteKBs))
+			} else {
+				isNew = true
+				line = fmt.Sprintf("%-*s %5d %5.1f %27s", w, cmd, p.PID, p.CPU, "NEW")
+			}
+		}
 
 		style := rowStyle
-		if p.FDDiff > 100 {
+		if isNew {
+			style = style.Foreground(lipgloss.Color(successColor)).Bold(true)
+		} else if p.State == "D" {
+			style = style.Foreground(lipgloss.Color(hotColor)).Bold(true)
+		} else if p.State == "Z" {
+			style = style.Foreground(lipgloss.Color(zombieColor)).Italic(true)
+		} else if p.FDDiff > 100 {
 			style = style.Foreground(lipgloss.Color(warningColor)).Bold(true)
 		} else if p.Nice > 0 {
 			style = style.Foreground(lipgloss.Color(secondaryColor))
 		} else if i == 0 {
-			style = style.Foreground(lipgloss.Color(highlightColor)).Bold(true)
+			style = style.Foreground(lipgloss.Color(opts.highlightColor)).Bold(true)
 		} else if i%2 == 0 {
 			style = dimStyle
 		}
-		b.WriteString(style.Render(line) + "\n")
+		b.WriteString(renderMatch(line, cmd, opts.match, style) + "\n")
 	}
 	return b.String()
 }
 
+// renderMatch renders line with style, underlining the first case-insensitive
+// occurrence of match inside the leading cmd field.
+func renderMatch(line, cmd, match string, style lipgloss.Style) string {
+	if match == "" {
+		return style.Render(line)
+	}
+	idx := strings.Index(strings.ToLower(cmd), strings.ToLower(match))
+	if idx < 0 || len(strings.ToLower(cmd)) != len(cmd) {
+		return style.Render(line)
+	}
+	end := idx + len(match)
+	hl := style.Bold(true).Underline(true).Foreground(lipgloss.Color(warningColor))
+	return style.Render(line[:idx]) + hl.Render(line[idx:end]) + style.Render(line[end:])
+}
+
 // renderProcessTableCompact renders a minimal process table for the right panel
 func renderProcessTableCompact(procs []model.Process, height int, highlightColor string) string {
 	var b strings.Builder
@@ -1478,7 +3220,7 @@ func renderIOTable(procs []model.Process, height int, width int) string {
 		}
 
 		// Format: CMD R:xxxx W:xxxx
-		line := fmt.Sprintf("%-*s R:%5.0f W:%5.0f", cmdWidth, cmd, p.ReadKBs, p.WriteKBs)
+		line := fmt.Sprintf("%-*s R:%5s W:%5s", cmdWidth, cmd, formatRate(p.ReadKBs*kib), formatRate(p.WriteKBs*kib))
 		b.WriteString(style.Render(line) + "\n")
 	}
 	return b.String()
@@ -1508,9 +3250,9 @@ func renderFDTable(procs []model.Process, height int, width int) string {
 		}
 
 		// Show FD count and diff if significant
-		fdStr := fmt.Sprintf("%5d", p.FDCount)
+		fdStr := fmt.Sprintf("%5s", formatInt(int64(p.FDCount)))
 		if p.FDDiff > 10 {
-			fdStr = fmt.Sprintf("%5d +%d", p.FDCount, p.FDDiff)
+			fdStr += " +" + formatInt(int64(p.FDDiff))
 		}
 
 		line := fmt.Sprintf("%-*s %s", cmdWidth, cmd, fdStr)
@@ -1520,7 +3262,7 @@ func renderFDTable(procs []model.Process, height int, width int) string {
 }
 
 // renderCoreGridCompact renders a more compact CPU core grid for smaller spaces
-func renderCoreGridCompact(hist map[int][]float64, width int) string {
+func renderCoreGridCompact(hist map[int][]float64, width int, spark sparkFunc) string {
 	var keys []int
 	for k := range hist {
 		keys = append(keys, k)
@@ -1536,7 +3278,7 @@ func renderCoreGridCompact(hist map[int][]float64, width int) string {
 		var lineParts []string
 		for j := 0; j < coresPerLine && i+j < len(keys); j++ {
 			c := keys[i+j]
-			sp := renderSparklinePct(hist[c], sparkWidth, primaryColor)
+			sp := spark(hist[c], sparkWidth, primaryColor)
 			lineParts = append(lineParts, fmt.Sprintf("%2d%s", c, sp))
 		}
 		lines = append(lines, strings.Join(lineParts, " "))
@@ -1550,7 +3292,7 @@ func renderCoreGridCompact(hist map[int][]float64, width int) string {
 	return strings.Join(lines, "\n")
 }
 
-func renderCoreGrid(hist map[int][]float64, width int) string {
+func renderCoreGrid(hist map[int][]float64, width int, spark sparkFunc) string {
 	// Create a simple grid. We assume we have hist points.
 	// Sort keys
 	var keys []int
@@ -1563,12 +3305,12 @@ func renderCoreGrid(hist map[int][]float64, width int) string {
 	// 2 columns of cores
 	for i := 0; i < len(keys); i += 2 {
 		c1 := keys[i]
-		sp1 := renderSparklinePct(hist[c1], 10, primaryColor) // mini sparklines
+		sp1 := spark(hist[c1], 10, primaryColor) // mini sparklines
 		line := fmt.Sprintf("%2d %s", c1, sp1)
 
 		if i+1 < len(keys) {
 			c2 := keys[i+1]
-			sp2 := renderSparklinePct(hist[c2], 10, primaryColor)
+			sp2 := spark(hist[c2], 10, primaryColor)
 			line += fmt.Sprintf("   %2d %s", c2, sp2)
 		}
 		lines = append(lines, line)
@@ -1583,18 +3325,12 @@ func renderSparklineWithStats(values []float64, width int, color string) string
 		return strings.Repeat(" ", width)
 	}
 
-	// Calculate stats
-	min, max, sum := values[0], values[0], 0.0
+	max := values[0]
 	for _, v := range values {
-		if v < min {
-			min = v
-		}
 		if v > max {
 			max = v
 		}
-		sum += v
 	}
-	avg := sum / float64(len(values))
 
 	// Take last N values for sparkline
 	sparkWidth := width - 16 // Leave room for stats
@@ -1634,10 +3370,23 @@ func renderSparklineWithStats(values []float64, width int, color string) string
 		}
 	}
 
-	// Add stats suffix
-	statsStyle := subtleStyle
-	stats := fmt.Sprintf(" ↑%.0f ↓%.0f ~%.0f", max, min, avg)
-	return style.Render(b.String()) + statsStyle.Render(stats)
+	return style.Render(b.String()) + sparkStatsLabel(values)
+}
+
+// sparkStatsLabel is the max/min/avg suffix of the stats sparklines.
+func sparkStatsLabel(values []float64) string {
+	min, max, sum := values[0], values[0], 0.0
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	avg := sum / float64(len(values))
+	return subtleStyle.Render(fmt.Sprintf(" ↑%.0f ↓%.0f ~%.0f", max, min, avg))
 }
 
 // renderProcDetailModal renders a modal with detailed process information
@@ -1670,25 +3419,66 @@ func (m *Model) renderProcDetailModal(s model.Sample) string {
 	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
 	modalLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(labelColor)).Width(12)
 
-	rows := []struct {
+	type detailRow struct {
 		label string
 		value string
-	}{
-		{"Command", proc.Command},
-		{"PID", fmt.Sprintf("%d", proc.PID)},
+	}
+	rows := []detailRow{
+		{"Command", truncate(proc.Command, 44)},
+		{"PID", formatInt(int64(proc.PID))},
 		{"Nice", fmt.Sprintf("%d", proc.Nice)},
-		{"CPU", fmt.Sprintf("%.1f%%", proc.CPU)},
+		{"State", stateName(proc.State)},
+		{"CPU", fmt.Sprintf("%.1f%% (%.1f%% of machine)", proc.CPU, proc.CPUNorm)},
 		{"Memory", fmt.Sprintf("%.1f%%", proc.Memory)},
-		{"Read", fmt.Sprintf("%.1f kB/s", proc.ReadKBs)},
-		{"Write", fmt.Sprintf("%.1f kB/s", proc.WriteKBs)},
-		{"FD Count", fmt.Sprintf("%d", proc.FDCount)},
+		{"Read", deniedOr(proc.IODenied, fmt.Sprintf("%s/s (%s total)", formatRate(proc.ReadKBs*kib), formatBytes(proc.ReadTotal)))},
+		{"Write", deniedOr(proc.IODenied, fmt.Sprintf("%s/s (%s total)", formatRate(proc.WriteKBs*kib), formatBytes(proc.WriteTotal)))},
+		{"Faults", fmt.Sprintf("%.0f/s major · %.0f/s minor", proc.MajorFaults, proc.MinorFaults)},
+		{"IO delay", ioDelayText(*proc)},
+		{"FD Count", deniedOr(proc.FDDenied, formatInt(int64(proc.FDCount)))},
 		{"FD Change", fmt.Sprintf("%+d", proc.FDDiff)},
+		{"Mem Change", formatSignedBytes(proc.MemDiff)},
+		{"Sockets", formatInt(int64(proc.Conns))},
+		{"OOM Score", fmt.Sprintf("%d (adj %+d)", proc.OOMScore, proc.OOMScoreAdj)},
+	}
+
+	for _, gp := range s.GPUProcs {
+		if gp.PID == proc.PID {
+			rows = append(rows, detailRow{"GPU mem", fmt.Sprintf("%.0fMB", gp.MemUsedMB)})
+			break
+		}
 	}
 
 	for _, r := range rows {
 		content.WriteString(modalLabelStyle.Render(r.label+":") + " " + infoStyle.Render(r.value) + "\n")
 	}
 
+	cmdline := proc.Command
+	if info := m.detailInfo; info.PID == proc.PID && info.Cmdline != "" {
+		cmdline = info.Cmdline
+	}
+	content.WriteString("\n" + subtleStyle.Render("Full command line:") + "\n")
+	content.WriteString(wrapCapped(cmdline, infoStyle, 56, max(m.height-36, 4)) + "\n")
+
+	if info := m.detailInfo; info.PID == proc.PID {
+		if info.Exe != "" {
+			content.WriteString(modalLabelStyle.Render("Exe:") + " " + infoStyle.Render(info.Exe) + "\n")
+		}
+		if m.detailShowEnv {
+			content.WriteString("\n" + subtleStyle.Render(fmt.Sprintf("Environment (%d vars, secrets redacted):", len(info.Env))) + "\n")
+			maxEnv := max(m.height-40, 5)
+			for i, kv := range info.Env {
+				if i >= maxEnv {
+					content.WriteString(subtleStyle.Render(fmt.Sprintf("  ... and %d more", len(info.Env)-maxEnv)) + "\n")
+					break
+				}
+				content.WriteString(dimStyle.Render(truncate(kv, 56)) + "\n")
+			}
+			if len(info.Env) == 0 {
+				content.WriteString(dimStyle.Render("(not readable)") + "\n")
+			}
+		}
+	}
+
 	// Mini gauges for CPU and Memory
 	content.WriteString("\n")
 	content.WriteString(modalLabelStyle.Render("CPU:") + " " + renderMiniGauge(proc.CPU, 30) + "\n")
@@ -1697,11 +3487,21 @@ func (m *Model) renderProcDetailModal(s model.Sample) string {
 	// Action hints
 	content.WriteString("\n")
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(labelColor)).Italic(true)
-	content.WriteString(hintStyle.Render("Tip: sudo ionice -c3 -p " + fmt.Sprintf("%d", proc.PID) + " to throttle IO"))
+	content.WriteString(hintStyle.Render("Tip: sudo ionice -c3 -p " + fmt.Sprintf("%d", proc.PID) + " to throttle IO (i)"))
 	content.WriteString("\n")
-	content.WriteString(hintStyle.Render("     sudo renice +10 -p " + fmt.Sprintf("%d", proc.PID) + " to lower priority"))
-	content.WriteString("\n\n")
-	content.WriteString(subtleStyle.Render("Press ESC or Enter to close"))
+	if proc.Nice < tipNice {
+		content.WriteString(hintStyle.Render(fmt.Sprintf("     sudo renice +%d -p %d to set nice %d, lowering priority (n)", tipNice, proc.PID, tipNice)))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+	if m.detailNote != "" {
+		noteColor := successColor
+		if m.detailErr {
+			noteColor = hotColor
+		}
+		content.WriteString(wrapCapped(m.detailNote, lipgloss.NewStyle().Foreground(lipgloss.Color(noteColor)), 56, 3) + "\n")
+	}
+	content.WriteString(subtleStyle.Render("j/k step · v env · y copy · i/n tip (twice runs) · ESC"))
 
 	modal := modalStyle.Render(content.String())
 
@@ -1711,6 +3511,154 @@ func (m *Model) renderProcDetailModal(s model.Sample) string {
 		lipgloss.WithWhitespaceForeground(lipgloss.Color("#111111")))
 }
 
+// ioDelayText describes a process's block IO delay; a process that waits a
+// lot while moving little data is stuck behind someone else's IO.
+func ioDelayText(p model.Process) string {
+	txt := fmt.Sprintf("%.0f ms/s blocked on block IO", p.IOWaitMs)
+	if p.IOWaitMs >= 200 && p.ReadKBs+p.WriteKBs < 1024 {
+		txt += " (victim of IO contention)"
+	}
+	return txt
+}
+
+// deniedOr returns val, or a note that the value needs root when denied.
+func deniedOr(denied bool, val string) string {
+	if denied {
+		return "not readable (needs root)"
+	}
+	return val
+}
+
+// wrapCapped wraps s to width in style, keeping at most maxLines lines.
+func wrapCapped(s string, style lipgloss.Style, width, maxLines int) string {
+	lines := strings.Split(style.Width(width).Render(s), "\n")
+	if len(lines) > maxLines {
+		more := len(lines) - maxLines + 1
+		lines = append(lines[:maxLines-1], subtleStyle.Render(fmt.Sprintf("… %d more lines (y copies it all)", more)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// copyDetail copies the detail modal's process summary to the clipboard.
+func (m *Model) copyDetail() tea.Cmd {
+	for _, p := range m.latest.Top {
+		if p.PID != m.detailPID {
+			continue
+		}
+		host := m.cfg.Remote
+		if host == "" {
+			host, _ = os.Hostname()
+		}
+		m.detailNote, m.detailErr = fmt.Sprintf("Copying PID %d summary…", p.PID), false
+		return copyToClipboard(m.out, fmt.Sprintf("PID %d summary", p.PID), procSummary(p, m.detailInfo, host))
+	}
+	m.detailNote, m.detailErr = "Process not in the current sample", true
+	return nil
+}
+
+// handleKillConfirm advances the bulk kill confirmation; any key other than y cancels.
+func (m *Model) handleKillConfirm(key string) {
+	if key != "y" && key != "Y" {
+		m.killStage = 0
+		m.killTargets, m.killSpared = nil, nil
+		m.statusMsg = "Bulk kill cancelled"
+		return
+	}
+	if m.killStage == 1 && len(m.killTargets) > killSafetyCap {
+		m.killStage = 2
+		return
+	}
+	sent, failed := signalProcs(m.killTargets, syscall.SIGTERM)
+	m.killStage = 0
+	m.statusMsg = fmt.Sprintf("SIGTERM sent to %d processes (%d failed)", sent, failed)
+	if len(m.killSpared) > 0 {
+		m.statusMsg += fmt.Sprintf(", %d protected left alone", len(m.killSpared))
+	}
+	m.killTargets, m.killSpared = nil, nil
+}
+
+// signalProcs sends sig to each process, never to sysmoni itself.
+func signalProcs(procs []model.Process, sig syscall.Signal) (sent, failed int) {
+	self := os.Getpid()
+	for _, p := range procs {
+		if p.PID == self || p.PID <= 1 {
+			continue
+		}
+		if err := sendSignal(p.PID, sig); err != nil {
+			failed++
+			continue
+		}
+		sent++
+	}
+	return sent, failed
+}
+
+// renderKillConfirmModal lists the processes a bulk kill would signal
+func (m *Model) renderKillConfirmModal() string {
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.ThickBorder()).
+		BorderForeground(lipgloss.Color(criticalColor)).
+		Padding(1, 2).
+		Width(60)
+
+	totalCPU := 0.0
+	for _, p := range m.killTargets {
+		totalCPU += p.CPU
+	}
+
+	var content strings.Builder
+	content.WriteString(criticalStyle.Render("SIGTERM MATCHED PROCESSES"))
+	content.WriteString("\n\n")
+	content.WriteString(fmt.Sprintf("Filter %q matches %d processes using %.1f%% CPU\n\n", m.filter, len(m.killTargets), totalCPU))
+
+	shown := minInt(8, len(m.killTargets))
+	for _, p := range m.killTargets[:shown] {
+		content.WriteString(rowStyle.Render(fmt.Sprintf("%7d %5.1f%% %s", p.PID, p.CPU, truncate(p.Command, 38))) + "\n")
+	}
+	if len(m.killTargets) > shown {
+		content.WriteString(subtleStyle.Render(fmt.Sprintf("  ... and %d more", len(m.killTargets)-shown)) + "\n")
+	}
+	if len(m.killSpared) > 0 {
+		names := make([]string, 0, len(m.killSpared))
+		for _, p := range m.killSpared {
+			names = append(names, fmt.Sprintf("%s (%d)", truncate(p.Command, 16), p.PID))
+		}
+		content.WriteString("\n" + subtleStyle.Render(truncate("🔒 Protected, left alone: "+strings.Join(names, ", "), 54)) + "\n")
+	}
+	content.WriteString("\n")
+
+	if m.killStage == 2 {
+		content.WriteString(pulseStyle.Render(fmt.Sprintf("More than %d processes! Press y again to really send SIGTERM", killSafetyCap)))
+	} else {
+		content.WriteString(valStyle.Render("Press y to send SIGTERM, any other key to cancel"))
+	}
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(content.String()),
+		lipgloss.WithWhitespaceChars("░"),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("#111111")))
+}
+
+// renderQuitConfirmModal asks before leaving when -confirm-quit is set
+func (m *Model) renderQuitConfirmModal() string {
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color(primaryColor)).
+		Padding(1, 2).
+		Width(50)
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(primaryColor)).Render("QUIT SYSMONI?"))
+	content.WriteString("\n\n")
+	if m.jsonFile != "" {
+		content.WriteString(subtleStyle.Render(fmt.Sprintf("JSON output %s is flushed on exit", truncate(m.jsonFile, 20))) + "\n\n")
+	}
+	content.WriteString(valStyle.Render("y/q to quit · any other key to stay"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalStyle.Render(content.String()),
+		lipgloss.WithWhitespaceChars("░"),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("#111111")))
+}
+
 // renderSystemInfo renders the third tab with system details (temps, inotify, cgroups)
 func (m *Model) renderSystemInfo(s model.Sample) string {
 	availHeight := m.height - 4
@@ -1722,20 +3670,93 @@ func (m *Model) renderSystemInfo(s model.Sample) string {
 	inotifyCard := m.renderInotifyPanel(s.Inotify, availHeight/3)
 
 	// Cgroups panel
-	cgroupsCard := m.renderCgroupsPanel(s.Cgroups, availHeight/3)
+	var cgroupsCard string
+	switch cgroupViews[m.cgroupView] {
+	case "unit":
+		cgroupsCard = m.renderUnitsPanel(s.Units, availHeight/3)
+	case "container":
+		cgroupsCard = m.renderContainersPanel(s.Containers, availHeight/3)
+	default:
+		cgroupsCard = m.renderCgroupsPanel(s.Cgroups, availHeight/3)
+	}
 
 	// Layout: temps on left, inotify + cgroups on right
 	leftWidth := m.width / 2
 	rightWidth := m.width - leftWidth - 2
 
 	leftCol := lipgloss.NewStyle().Width(leftWidth).Render(tempsCard)
+	if m.linuxPanels() {
+		leftCol = lipgloss.JoinVertical(lipgloss.Left, leftCol,
+			lipgloss.NewStyle().Width(leftWidth).Render(m.renderLimitsPanel(s.Limits)))
+	}
+	if m.cfg.NetStates && m.linuxPanels() {
+		leftCol = lipgloss.JoinVertical(lipgloss.Left, leftCol,
+			lipgloss.NewStyle().Width(leftWidth).Render(m.renderNetStatesPanel(s.NetStates, availHeight/3)))
+	}
 	rightCol := lipgloss.JoinVertical(lipgloss.Left,
 		lipgloss.NewStyle().Width(rightWidth).Render(inotifyCard),
 		lipgloss.NewStyle().Width(rightWidth).Render(cgroupsCard))
+	if !m.linuxPanels() {
+		rightCol = lipgloss.NewStyle().Width(rightWidth).Render(cardStyle.Render(
+			subtleStyle.Render("Inotify, cgroup and systemd unit panels read Linux /proc and /sys;\nthey are not available on this system.")))
+	}
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, leftCol, rightCol)
 }
 
+// linuxPanels reports whether the procfs-only panels have data behind them:
+// always for a remote host (its side may be Linux), else when built for Linux.
+func (m *Model) linuxPanels() bool {
+	return m.remote != nil || sampler.LinuxPanels
+}
+
+// renderLimitsPanel shows the kernel's entropy estimate and file handle
+// table, colored like the inotify panel as they approach exhaustion.
+func (m *Model) renderLimitsPanel(l model.Limits) string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(primaryColor)).
+		Bold(true).
+		Render("🧮 KERNEL LIMITS")
+	content.WriteString(header + "\n\n")
+
+	labelW := lipgloss.NewStyle().Foreground(lipgloss.Color(labelColor)).Width(16)
+	level := func(pct float64) lipgloss.Style {
+		if pct > 90 {
+			return criticalStyle
+		} else if pct > 70 {
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor))
+		}
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(successColor))
+	}
+
+	if l.FilesMax >= 1<<62 {
+		// systemd raises fs.file-max to LONG_MAX: effectively unlimited
+		content.WriteString(labelW.Render("File handles:") + " " +
+			level(0).Render(formatCount(float64(l.FilesAllocated))) + subtleStyle.Render(" (no limit)") + "\n")
+	} else if l.FilesMax > 0 {
+		filesPct := l.FilesPct()
+		content.WriteString(labelW.Render("File handles:") + " " + renderMiniGauge(filesPct, 20) +
+			level(filesPct).Render(fmt.Sprintf(" %s / %s", formatCount(float64(l.FilesAllocated)), formatCount(float64(l.FilesMax)))) + "\n")
+	}
+	if l.EntropyPool > 0 {
+		// Scarce entropy is the danger, so color by how much of the pool is missing
+		entropyPct := float64(l.EntropyAvail) / float64(l.EntropyPool) * 100
+		content.WriteString(labelW.Render("Entropy:") + " " + renderMiniGauge(entropyPct, 20) +
+			level(100-entropyPct).Render(fmt.Sprintf(" %s / %s bits", formatInt(int64(l.EntropyAvail)), formatInt(int64(l.EntropyPool)))) + "\n")
+	}
+	if l == (model.Limits{}) {
+		content.WriteString(dimStyle.Render("Collecting..."))
+	}
+	if m.criticalFD {
+		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Italic(true)
+		content.WriteString("\n" + warnStyle.Render("⚠ File handle table nearly full (raise fs.file-max)"))
+	}
+
+	return cardStyle.Render(strings.TrimRight(content.String(), "\n"))
+}
+
 // renderTempsPanel renders temperature readings with thermal coloring
 func (m *Model) renderTempsPanel(temps []model.Temp, height int) string {
 	var content strings.Builder
@@ -1836,12 +3857,24 @@ func (m *Model) renderInotifyPanel(info model.Inotify, height int) string {
 	labelW := lipgloss.NewStyle().Foreground(lipgloss.Color(labelColor)).Width(16)
 	valW := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
 
-	content.WriteString(labelW.Render("Current:") + " " + usageStyle.Render(fmt.Sprintf("%d", info.NrWatches)) + "\n")
-	content.WriteString(labelW.Render("Max User:") + " " + valW.Render(fmt.Sprintf("%d", info.MaxUserWatches)) + "\n")
-	content.WriteString(labelW.Render("Max Instances:") + " " + valW.Render(fmt.Sprintf("%d", info.MaxUserInstances)) + "\n")
+	content.WriteString(labelW.Render("Current:") + " " + usageStyle.Render(formatInt(int64(info.NrWatches))) + "\n")
+	content.WriteString(labelW.Render("Max User:") + " " + valW.Render(formatInt(int64(info.MaxUserWatches))) + "\n")
+	content.WriteString(labelW.Render("Max Instances:") + " " + valW.Render(formatInt(int64(info.MaxUserInstances))) + "\n")
 	content.WriteString("\n")
 	content.WriteString(labelW.Render("Usage:") + " " + renderMiniGauge(usagePct, 20) + usageStyle.Render(fmt.Sprintf(" %.1f%%", usagePct)) + "\n")
 
+	if len(info.Procs) > 0 {
+		content.WriteString("\n" + subtleStyle.Render("Top holders:") + "\n")
+		// header, gauge block and warning use ~10 lines
+		rows := len(info.Procs)
+		if maxRows := height - 10; rows > maxRows {
+			rows = maxRows
+		}
+		for _, p := range info.Procs[:max(rows, 0)] {
+			content.WriteString(fmt.Sprintf("  %-16s %7d %s\n", truncate(p.Command, 16), p.PID, valW.Render(fmt.Sprintf("%6d", p.Watches))))
+		}
+	}
+
 	if usagePct > 80 {
 		content.WriteString("\n")
 		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Italic(true)
@@ -1851,6 +3884,128 @@ func (m *Model) renderInotifyPanel(info model.Inotify, height int) string {
 	return cardStyle.Height(height).Render(content.String())
 }
 
+// renderNetStatesPanel renders the socket state tally
+func (m *Model) renderNetStatesPanel(ns *model.NetStates, height int) string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(primaryColor)).
+		Bold(true).
+		Render("🔌 SOCKET STATES")
+	content.WriteString(header + "\n\n")
+
+	if ns == nil {
+		content.WriteString(dimStyle.Render("Collecting..."))
+		return cardStyle.Height(height).Render(content.String())
+	}
+
+	labelW := lipgloss.NewStyle().Foreground(lipgloss.Color(labelColor)).Width(14)
+	valW := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	rows := []struct {
+		label string
+		n     int
+	}{
+		{"ESTABLISHED", ns.Established},
+		{"LISTEN", ns.Listen},
+		{"TIME_WAIT", ns.TimeWait},
+		{"CLOSE_WAIT", ns.CloseWait},
+		{"SYN_SENT", ns.SynSent + ns.SynRecv},
+		{"FIN_WAIT", ns.FinWait1 + ns.FinWait2 + ns.Closing + ns.LastAck},
+		{"UDP", ns.UDP},
+	}
+	for _, r := range rows {
+		style := valW
+		// CLOSE_WAIT means the local side never closed: a classic fd/socket leak
+		if r.label == "CLOSE_WAIT" && r.n > 100 {
+			style = criticalStyle
+		} else if r.label == "CLOSE_WAIT" && r.n > 10 {
+			style = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor))
+		}
+		content.WriteString(labelW.Render(r.label) + " " + style.Render(fmt.Sprintf("%6d", r.n)) + "\n")
+	}
+
+	return cardStyle.Height(height).Render(content.String())
+}
+
+// cgroupViews are the groupings u cycles the cgroup panel through.
+var cgroupViews = []string{"cgroup", "unit", "container"}
+
+// renderContainersPanel renders per-container CPU/mem, docker stats style
+func (m *Model) renderContainersPanel(ctrs []model.Container, height int) string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(primaryColor)).
+		Bold(true).
+		Render("🐳 CONTAINERS")
+	content.WriteString(header + "\n\n")
+
+	if len(ctrs) == 0 {
+		content.WriteString(subtleStyle.Render("No docker/podman/CRI containers found\n"))
+		return cardStyle.Height(height).Render(content.String())
+	}
+
+	content.WriteString(subtleStyle.Render(fmt.Sprintf("%-28s %6s %8s %5s", "CONTAINER", "CPU", "MEM", "PROCS")) + "\n")
+	maxShown := height - 4
+	if maxShown < 1 {
+		maxShown = 1
+	}
+	for i, c := range ctrs {
+		if i >= maxShown {
+			content.WriteString(subtleStyle.Render(fmt.Sprintf("  ... and %d more", len(ctrs)-maxShown)) + "\n")
+			break
+		}
+		cpuStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+		if c.CPU > 80 {
+			cpuStyle = criticalStyle
+		} else if c.CPU > 50 {
+			cpuStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor))
+		}
+		content.WriteString(fmt.Sprintf("%-28s %s %8s %5d\n", truncate(c.Name, 28),
+			cpuStyle.Render(fmt.Sprintf("%5.1f%%", c.CPU)), formatBytes(c.MemBytes), c.Procs))
+	}
+
+	return cardStyle.Height(height).Render(content.String())
+}
+
+// renderUnitsPanel renders per-systemd-unit CPU/mem, systemd-cgtop style
+func (m *Model) renderUnitsPanel(units []model.Unit, height int) string {
+	var content strings.Builder
+
+	header := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(primaryColor)).
+		Bold(true).
+		Render("📦 SYSTEMD UNITS")
+	content.WriteString(header + "\n\n")
+
+	if len(units) == 0 {
+		content.WriteString(subtleStyle.Render("No .service/.scope cgroups found\n"))
+		return cardStyle.Height(height).Render(content.String())
+	}
+
+	content.WriteString(subtleStyle.Render(fmt.Sprintf("%-28s %6s %8s %5s", "UNIT", "CPU", "MEM", "PROCS")) + "\n")
+	maxShown := height - 4
+	if maxShown < 1 {
+		maxShown = 1
+	}
+	for i, u := range units {
+		if i >= maxShown {
+			content.WriteString(subtleStyle.Render(fmt.Sprintf("  ... and %d more", len(units)-maxShown)) + "\n")
+			break
+		}
+		cpuStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+		if u.CPU > 80 {
+			cpuStyle = criticalStyle
+		} else if u.CPU > 50 {
+			cpuStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor))
+		}
+		content.WriteString(fmt.Sprintf("%-28s %s %8s %5d\n", truncate(u.Name, 28),
+			cpuStyle.Render(fmt.Sprintf("%5.1f%%", u.CPU)), formatBytes(u.MemBytes), u.Procs))
+	}
+
+	return cardStyle.Height(height).Render(content.String())
+}
+
 // renderCgroupsPanel renders cgroup CPU usage summary
 func (m *Model) renderCgroupsPanel(cgroups []model.Cgroup, height int) string {
 	var content strings.Builder
@@ -1858,7 +4013,7 @@ func (m *Model) renderCgroupsPanel(cgroups []model.Cgroup, height int) string {
 	header := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(primaryColor)).
 		Bold(true).
-		Render("📦 CGROUP CPU USAGE")
+		Render("📦 CGROUP USAGE")
 	content.WriteString(header + "\n\n")
 
 	if len(cgroups) == 0 {
@@ -1896,7 +4051,14 @@ func (m *Model) renderCgroupsPanel(cgroups []model.Cgroup, height int) string {
 			}
 
 			bar := renderMiniGauge(cpuPct, 12)
-			content.WriteString(fmt.Sprintf("%-25s %s %s\n", name, bar, cpuStyle.Render(fmt.Sprintf("%5.1f%%", cpuPct))))
+			extra := ""
+			if cg.MemBytes > 0 {
+				extra = subtleStyle.Render(fmt.Sprintf(" mem %7s", formatBytes(cg.MemBytes)))
+			}
+			if cg.IOReadBytes+cg.IOWriteBytes > 0 {
+				extra += subtleStyle.Render(fmt.Sprintf(" io R%s W%s", formatBytes(cg.IOReadBytes), formatBytes(cg.IOWriteBytes)))
+			}
+			content.WriteString(fmt.Sprintf("%-25s %s %s%s\n", name, bar, cpuStyle.Render(fmt.Sprintf("%5.1f%%", cpuPct)), extra))
 		}
 	}
 
@@ -1906,22 +4068,22 @@ func (m *Model) renderCgroupsPanel(cgroups []model.Cgroup, height int) string {
 // --- Scrolling helpers for the Top table ---
 
 func (m *Model) topLayout() (columns int, maxRows int) {
-	availHeight := m.height - 22
-	if availHeight > 20 {
-		availHeight = 20 // Match the cap in renderDashboard
-	}
-	if availHeight < 6 {
-		availHeight = 6
+	if m.minimal {
+		columns = 1
+		if m.width >= 100 {
+			columns = 2
+		}
+		if m.width >= 140 {
+			columns = 3
+		}
+		return m.fitProcColumns(columns, m.width-1), m.minimalRows() - 1
 	}
+	availHeight := m.procsHeight()
 
-	// Calculate columns based on screen width (matches renderDashboard logic)
+	// Calculate columns based on screen width (matches renderProcsCard logic)
 	if m.width >= 160 {
 		// Wide screens: have a right panel for IO/FD/throttled/cores
-		rightWidth := minInt(44, m.width/4)
-		if rightWidth < 36 {
-			rightWidth = 36
-		}
-		procAreaWidth := m.width - rightWidth - 3
+		procAreaWidth := m.width - m.rightPanelWidth() - 3
 		columns = 1
 		if procAreaWidth >= 80 {
 			columns = 2
@@ -1932,6 +4094,7 @@ func (m *Model) topLayout() (columns int, maxRows int) {
 		if procAreaWidth >= 160 {
 			columns = 4
 		}
+		columns = m.fitProcColumns(columns, procAreaWidth-4)
 	} else {
 		// Narrow screens: no right panel, full width for processes
 		columns = 1
@@ -1941,22 +4104,84 @@ func (m *Model) topLayout() (columns int, maxRows int) {
 		if m.width >= 140 {
 			columns = 3
 		}
+		columns = m.fitProcColumns(columns, m.width-6)
 	}
 
-	maxRows = availHeight - 1
+	maxRows = availHeight - 1 - m.pinnedLines() - m.filterSumLines()
 	if maxRows < 1 {
 		maxRows = 1
 	}
 	return
 }
 
+// fitProcColumns lowers the number of side-by-side table columns until each
+// has room for the enabled metric columns plus a readable CMD.
+func (m *Model) fitProcColumns(cols, width int) int {
+	fixed := fixedColumnsWidth(m.procColumns())
+	if m.baselineByPID != nil {
+		fixed = 40
+	}
+	need := fixed + 1 + 12
+	for cols > 1 && width/cols < need {
+		cols--
+	}
+	return cols
+}
+
+// Right panel width bounds when the split is dragged
+const (
+	minRightPanel = 24
+	minProcArea   = 60
+)
+
+// rightPanelWidth is the dashboard's right column width on wide screens:
+// the dragged split when set, else min(44, width/4) but at least 36.
+func (m *Model) rightPanelWidth() int {
+	if m.splitRatio > 0 {
+		w := int(m.splitRatio * float64(m.width))
+		return maxInt(minRightPanel, minInt(w, m.width-minProcArea))
+	}
+	return maxInt(36, minInt(44, m.width/4))
+}
+
+// onSplitBorder reports whether (x, y) hits the border between the process
+// card and the right card: the process card's right edge, the margin, or the
+// right card's left edge.
+func (m *Model) onSplitBorder(x, y int) bool {
+	if m.minimal || m.activeTab != 0 || m.width < 160 || y < m.placed.procsTop || y >= m.placed.procsBottom {
+		return false
+	}
+	edge := m.width - m.rightPanelWidth() - 3 + 1 // procAreaWidth + left border
+	return x >= edge && x <= edge+2
+}
+
+// setSplitFromX moves the split so the process card's right border sits at x.
+func (m *Model) setSplitFromX(x int) {
+	right := maxInt(minRightPanel, minInt(m.width-x-2, m.width-minProcArea))
+	m.splitRatio = float64(right) / float64(m.width)
+	m.clampTopOffset()
+	m.statusMsg = fmt.Sprintf("Right panel: %d cols", right)
+}
+
+// saveSplit persists the dragged split ratio to the config file.
+func (m *Model) saveSplit() {
+	if m.cfg.File == "" {
+		return
+	}
+	if err := config.SaveValue(m.cfg.File, "split_ratio", fmt.Sprintf("%.3f", m.splitRatio)); err != nil {
+		m.statusMsg = fmt.Sprintf("Split not saved: %v", err)
+		return
+	}
+	m.statusMsg = fmt.Sprintf("Split saved to %s", m.cfg.File)
+}
+
 func (m *Model) visibleTopCapacity() int {
 	cols, rows := m.topLayout()
 	return maxInt(1, cols*rows)
 }
 
 func (m *Model) maxTopOffset() int {
-	total := len(m.sortAndFilter(m.latest.Top))
+	total := len(m.topRows(m.latest))
 	capacity := m.visibleTopCapacity()
 	maxOff := total - capacity
 	if maxOff < 0 {
@@ -2001,6 +4226,151 @@ func pct(used, total uint64) float64 {
 	return float64(used) * 100 / float64(total)
 }
 
+// siUnits scales formatBytes and formatRate by 1000 instead of 1024; New sets
+// it from config.SIUnits.
+var siUnits bool
+
+// thousandsSep groups the digits of exact counts in formatInt; New sets it
+// from the locale when config.Thousands is on, else it is "" (no grouping).
+var thousandsSep string
+
+// localeThousandsSep picks the digit group separator for the user's locale
+// (LC_ALL, LC_NUMERIC, then LANG): "." where the decimal mark is a comma,
+// a no-break space in locales that group with spaces, "'" in Switzerland,
+// and "," everywhere else, including C/POSIX.
+func localeThousandsSep() string {
+	loc := ""
+	for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if loc = os.Getenv(env); loc != "" {
+			break
+		}
+	}
+	loc, _, _ = strings.Cut(loc, ".")
+	lang, region, _ := strings.Cut(loc, "_")
+	switch {
+	case region == "CH" || region == "LI":
+		return "'"
+	case strings.Contains(" fr ru pl cs sk sv fi nb nn no uk bg hu et lv lt ", " "+lang+" "):
+		return "\u00a0"
+	case strings.Contains(" de es it nl pt da id tr el ro hr sl sr ", " "+lang+" "):
+		return "."
+	}
+	return ","
+}
+
+// setNumberFormat applies the config's unit and digit grouping choices to
+// the formatting helpers.
+func setNumberFormat(cfg config.Config) {
+	siUnits = cfg.SIUnits
+	thousandsSep = ""
+	if cfg.Thousands {
+		thousandsSep = localeThousandsSep()
+	}
+}
+
+// formatInt renders an exact count, grouping its digits with thousandsSep.
+func formatInt(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if thousandsSep == "" {
+		return s
+	}
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteString(thousandsSep)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// formatBytes renders a byte count with a unit suffix, e.g. "1.5G" or "512M".
+// At most five characters wide, so it fits fixed-width columns.
+func formatBytes(b uint64) string {
+	unit := uint64(1024)
+	if siUnits {
+		unit = 1000
+	}
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := unit, 0
+	for n := b / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return compactFloat(float64(b)/float64(div)) + string("KMGTP"[exp])
+}
+
+// formatRate renders a rate given in bytes/s like formatBytes, without the
+// "/s"; an idle rate is "0" rather than "0B".
+func formatRate(bps float64) string {
+	if bps < 0.5 {
+		return "0"
+	}
+	return formatBytes(uint64(bps + 0.5))
+}
+
+// formatBitRate renders a network rate given in Mb/s, e.g. "940Mb/s" or
+// "1.2Gb/s". Link rates are always decimal, whatever siUnits says.
+func formatBitRate(mbps float64) string {
+	v := mbps * 1e6
+	if v < 0.5 {
+		return "0b/s"
+	}
+	exp := -1
+	for v >= 1000 && exp < 3 {
+		v /= 1000
+		exp++
+	}
+	if exp < 0 {
+		return fmt.Sprintf("%.0fb/s", v)
+	}
+	return compactFloat(v) + string("kMGT"[exp]) + "b/s"
+}
+
+// formatCount renders an event count or rate with a decimal suffix, e.g.
+// "950", "12.3k" or "1.2M".
+func formatCount(v float64) string {
+	exp := -1
+	for v >= 999.5 && exp < 2 {
+		v /= 1000
+		exp++
+	}
+	if exp < 0 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return compactFloat(v) + string("kMG"[exp])
+}
+
+// compactFloat keeps one decimal below 100 and drops it above.
+func compactFloat(v float64) string {
+	if v < 99.95 {
+		return fmt.Sprintf("%.1f", v)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// formatHM renders seconds as "2h43m" (or "43m" under an hour).
+func formatHM(secs int64) string {
+	h, mnt := secs/3600, (secs%3600)/60
+	if h == 0 {
+		return fmt.Sprintf("%dm", mnt)
+	}
+	return fmt.Sprintf("%dh%02dm", h, mnt)
+}
+
+// kib and mib convert the sampler's KiB/s and MiB/s rates to bytes/s.
+const (
+	kib = 1024
+	mib = 1024 * 1024
+)
+
 func bytesToGiB(b uint64) float64 { return float64(b) / (1024 * 1024 * 1024) }
 
 func truncate(s string, n int) string {
@@ -2031,32 +4401,310 @@ func onOff(v bool) string {
 	return "off"
 }
 
+// sortAndFilter returns the rows the process table shows: filtered by the
+// active filter (unless highlight mode keeps every row) and sorted.
 func (m *Model) sortAndFilter(rows []model.Process) []model.Process {
-	// Filter
+	rows = m.viewRows(rows)
+	if m.highlightMode {
+		return m.sortProcs(rows)
+	}
+	return m.sortProcs(filterProcs(rows, m.filter))
+}
+
+// filterMatches returns only the rows matching the applied filter, regardless of highlight mode.
+func (m *Model) filterMatches(rows []model.Process) []model.Process {
+	return m.sortProcs(filterProcs(m.viewRows(rows), m.filter))
+}
+
+// viewRows copies sampled rows into their displayed form: CPU normalization,
+// thresholds and name mode applied.
+func (m *Model) viewRows(rows []model.Process) []model.Process {
+	return m.applyNameMode(m.applyThresholds(m.applyCPUMode(rows)))
+}
+
+// applyCPUMode swaps in the machine-normalized CPU figure when enabled,
+// returning a copy so the sample itself keeps the raw value.
+func (m *Model) applyCPUMode(rows []model.Process) []model.Process {
+	if !m.cfg.CPUNorm {
+		return rows
+	}
+	out := make([]model.Process, len(rows))
+	for i, p := range rows {
+		p.CPU = p.CPUNorm
+		out[i] = p
+	}
+	return out
+}
+
+// nameModes is the order N cycles the CMD display through.
+var nameModes = []string{"cmdline", "comm", "exe"}
+
+// CheckNameMode reports a -name value other than cmdline, comm or exe.
+func CheckNameMode(mode string) error { return checkChoice(mode, nameModes) }
+
+func checkChoice(v string, choices []string) error {
+	for _, c := range choices {
+		if v == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown value %q (want %s)", v, strings.Join(choices, ", "))
+}
+
+// applyNameMode rewrites Command in place to the configured display form;
+// rows must be a private copy. Empty pieces fall back to the sampled Command.
+func (m *Model) applyNameMode(rows []model.Process) []model.Process {
+	for i := range rows {
+		p := &rows[i]
+		switch m.nameMode {
+		case "comm":
+			if p.Comm != "" {
+				p.Command = p.Comm
+			}
+		case "exe":
+			if p.Exe != "" {
+				p.Command = filepath.Base(p.Exe)
+			} else if p.Comm != "" {
+				p.Command = p.Comm
+			}
+		}
+		// "cmdline" is the sampled Command itself
+	}
+	return rows
+}
+
+// applyThresholds drops rows under the -min-cpu/-min-mem limits or outside
+// the -states filter; it always returns a fresh slice.
+func (m *Model) applyThresholds(rows []model.Process) []model.Process {
+	out := make([]model.Process, 0, len(rows))
+	for _, r := range rows {
+		if r.CPU < m.cfg.MinCPU || r.Memory < m.cfg.MinMem || !stateShown(m.cfg.States, r.State) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// thresholdLabel describes active thresholds for the header, or "" when none are set.
+func (m *Model) thresholdLabel() string {
+	var parts []string
+	if m.cfg.MinCPU > 0 {
+		parts = append(parts, fmt.Sprintf("cpu≥%g%%", m.cfg.MinCPU))
+	}
+	if m.cfg.MinMem > 0 {
+		parts = append(parts, fmt.Sprintf("mem≥%g%%", m.cfg.MinMem))
+	}
+	if m.cfg.States != "" && m.cfg.States != "all" {
+		parts = append(parts, "state:"+m.cfg.States)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
+
+// stateFilters is the order Z cycles -states through.
+var stateFilters = []string{"all", "active", "rd"}
+
+// CheckStates reports a -states value other than all, active or rd.
+func CheckStates(states string) error { return checkChoice(states, stateFilters) }
+
+// stateShown applies a -states filter to a ps state letter. Rows without a
+// state (rollup groups, old remotes) always pass.
+func stateShown(filter, state string) bool {
+	if state == "" {
+		return true
+	}
+	switch filter {
+	case "active":
+		return state != "S" && state != "I"
+	case "rd":
+		return state == "R" || state == "D"
+	}
+	return true
+}
+
+// stateName spells out a ps state letter for the detail view.
+func stateName(state string) string {
+	switch state {
+	case "R":
+		return "R (running)"
+	case "S":
+		return "S (sleeping)"
+	case "D":
+		return "D (uninterruptible sleep, usually waiting on IO)"
+	case "Z":
+		return "Z (zombie, waiting for its parent to reap it)"
+	case "T":
+		return "T (stopped)"
+	case "I":
+		return "I (idle kernel thread)"
+	case "":
+		return "unknown"
+	}
+	return state
+}
+
+// nextPreset returns the preset following cur, wrapping to the first.
+func nextPreset(presets []float64, cur float64) float64 {
+	for _, p := range presets {
+		if p > cur {
+			return p
+		}
+	}
+	return presets[0]
+}
+
+// intervalPresets are the refresh intervals d cycles through.
+var intervalPresets = []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second, 5 * time.Second}
+
+// nextInterval returns the interval preset following cur, wrapping to the
+// first.
+func nextInterval(cur time.Duration) time.Duration {
+	for _, p := range intervalPresets {
+		if p > cur {
+			return p
+		}
+	}
+	return intervalPresets[0]
+}
+
+func filterProcs(rows []model.Process, pattern string) []model.Process {
 	var filtered []model.Process
-	filterLower := strings.ToLower(m.filter)
+	filterLower := strings.ToLower(pattern)
 	for _, r := range rows {
 		if filterLower != "" && !strings.Contains(strings.ToLower(r.Command), filterLower) {
 			continue
 		}
 		filtered = append(filtered, r)
 	}
-	// Sort based on current sort key
+	return filtered
+}
+
+// sortKeys is the order `s` cycles through.
+var sortKeys = []string{"cpu", "mem", "io", "fd", "conn", "oom", "dmem", "dfd", "majflt", "blkio"}
+
+// nextSortKey returns the key after cur, wrapping; unknown keys restart at cpu.
+func nextSortKey(cur string) string {
+	for i, k := range sortKeys {
+		if k == cur {
+			return sortKeys[(i+1)%len(sortKeys)]
+		}
+	}
+	return sortKeys[0]
+}
+
+// secondarySortKey is the tiebreak key in effect.
+func (m *Model) secondarySortKey() string {
+	if m.sortKey2 != "" {
+		return m.sortKey2
+	}
+	if m.sortKey == "cpu" {
+		return "mem"
+	}
+	return "cpu"
+}
+
+// sortValue is the field a sort key orders by; unknown keys mean CPU.
+func sortValue(p model.Process, key string) float64 {
+	switch key {
+	case "mem":
+		return p.Memory
+	case "io":
+		return p.ReadKBs + p.WriteKBs
+	case "fd":
+		return float64(p.FDCount)
+	case "conn":
+		return float64(p.Conns)
+	case "oom":
+		return float64(p.OOMScore)
+	case "dmem":
+		return float64(p.MemDiff)
+	case "dfd":
+		return float64(p.FDDiff)
+	case "majflt":
+		return p.MajorFaults
+	case "blkio":
+		return p.IOWaitMs
+	case "threads":
+		return float64(p.Threads)
+	default: // "cpu"
+		return p.CPU
+	}
+}
+
+func (m *Model) sortProcs(filtered []model.Process) []model.Process {
+	// Primary key, then secondary, then PID so equal rows never shuffle
+	key2 := m.secondarySortKey()
 	sort.Slice(filtered, func(i, j int) bool {
-		switch m.sortKey {
-		case "mem":
-			return filtered[i].Memory > filtered[j].Memory
-		case "io":
-			return (filtered[i].ReadKBs + filtered[i].WriteKBs) > (filtered[j].ReadKBs + filtered[j].WriteKBs)
-		case "fd":
-			return filtered[i].FDCount > filtered[j].FDCount
-		default: // "cpu"
-			return filtered[i].CPU > filtered[j].CPU
+		a, b := filtered[i], filtered[j]
+		if va, vb := sortValue(a, m.sortKey), sortValue(b, m.sortKey); va != vb {
+			return (va > vb) != m.sortAsc
+		}
+		if va, vb := sortValue(a, key2), sortValue(b, key2); va != vb {
+			return (va > vb) != m.sortAsc
 		}
+		return a.PID < b.PID
 	})
 	return filtered
 }
 
+// activePattern is the substring being searched: the live input while typing, else the applied filter.
+func (m *Model) activePattern() string {
+	if m.inputMode {
+		return strings.TrimSpace(string(m.inputBuf))
+	}
+	return m.filter
+}
+
+// scrollToFirstMatch moves the table so the first row matching the active pattern is visible.
+func (m *Model) scrollToFirstMatch() {
+	pattern := strings.ToLower(m.activePattern())
+	if pattern == "" {
+		return
+	}
+	for i, p := range m.topRows(m.latest) {
+		if strings.Contains(strings.ToLower(p.Command), pattern) {
+			m.topOffset = i
+			m.clampTopOffset()
+			return
+		}
+	}
+}
+
+// filterSumLines is the height of the footer withFilterSum adds: one line
+// while a filter is applied.
+func (m *Model) filterSumLines() int {
+	if m.filter == "" {
+		return 0
+	}
+	return 1
+}
+
+// withFilterSum appends a footer totalling the processes matching the
+// applied filter (in highlight mode too, where the table keeps every row).
+func (m *Model) withFilterSum(s model.Sample, table string, width int) string {
+	if m.filter == "" {
+		return table
+	}
+	matches := m.filterMatches(s.Top)
+	line := fmt.Sprintf("Σ %s matching", formatInt(int64(len(matches))))
+	if len(s.Top) < s.Tasks.Processes {
+		// The sample only carries the top processes (-top-n), so say so
+		// rather than pass a partial sum off as the total
+		line = fmt.Sprintf("Σ %s matching in top %s of %s", formatInt(int64(len(matches))),
+			formatInt(int64(len(s.Top))), formatInt(int64(s.Tasks.Processes)))
+	}
+	if len(matches) > 0 {
+		sum := sumGroup(m.filter, matches)
+		rss := uint64(sum.Memory / 100 * float64(s.Memory.TotalBytes))
+		line += fmt.Sprintf(": CPU %.1f%% · RSS %s (%.1f%%) · IO R %s/s W %s/s",
+			sum.CPU, formatBytes(rss), sum.Memory, formatRate(sum.ReadKBs*kib), formatRate(sum.WriteKBs*kib))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, table, subtleStyle.Render(truncate(line, width)))
+}
+
 func displayFilter(m *Model) string {
 	if m.inputMode {
 		return "/" + string(m.inputBuf)
@@ -2068,21 +4716,121 @@ func (m *Model) maybeWriteJSON(s model.Sample) {
 	if m.jsonFile == "" {
 		return
 	}
-	f, err := os.OpenFile(m.jsonFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if m.jsonOut == nil {
+		f, err := export.NewRotatingFile(m.jsonFile, int64(m.cfg.JSONMaxMB)<<20, m.cfg.JSONKeep, m.cfg.JSONGzip)
+		if err != nil {
+			return
+		}
+		m.jsonOut = f
+	}
+	v, err := m.jsonProj.Project(s)
 	if err != nil {
 		return
 	}
+	// Encode issues one Write per sample, so rotation never splits a line
+	_ = json.NewEncoder(m.jsonOut).Encode(v)
+}
+
+// closeJSON flushes, syncs and closes the JSON output file, if open.
+func (m *Model) closeJSON() {
+	if m.jsonOut == nil {
+		return
+	}
+	_ = m.jsonOut.Close()
+	m.jsonOut = nil
+}
+
+// teardown stops sampling and flushes outputs. Safe to call more than once.
+func (m *Model) teardown() {
+	m.ctxCancel()
+	m.closeJSON()
+}
+
+func (m *Model) quit() tea.Cmd {
+	m.saveView()
+	m.teardown()
+	return tea.Quit
+}
+
+// exportHistoryCSV dumps the sparkline history buffers to a timestamped CSV
+// file in the working directory and returns its path.
+func (m *Model) exportHistoryCSV() (string, error) {
+	n := len(m.timeHist)
+	if n == 0 {
+		return "", fmt.Errorf("no history yet")
+	}
+	// Buffers are trimmed in lockstep, but align from the newest end to be safe.
+	at := func(hist []float64, i int) float64 {
+		j := len(hist) - n + i
+		if j < 0 || j >= len(hist) {
+			return 0
+		}
+		return hist[j]
+	}
+	var cores []int
+	for c := range m.perCoreHist {
+		cores = append(cores, c)
+	}
+	sort.Ints(cores)
+
+	rows := make([]export.Row, n)
+	for i := 0; i < n; i++ {
+		perCore := make([]float64, len(cores))
+		for k, c := range cores {
+			perCore[k] = at(m.perCoreHist[c], i)
+		}
+		rows[i] = export.Row{
+			Time:      m.timeHist[i],
+			CPU:       at(m.cpuHist, i),
+			Mem:       at(m.memHist, i),
+			NetRx:     at(m.netRxHist, i),
+			NetTx:     at(m.netTxHist, i),
+			DiskRead:  at(m.diskReadHist, i),
+			DiskWrite: at(m.diskWriteHist, i),
+			PerCore:   perCore,
+		}
+	}
+
+	path := fmt.Sprintf("sysmoni-history-%s.csv", time.Now().Format("20060102-150405"))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
 	defer f.Close()
-	_ = json.NewEncoder(f).Encode(s)
+	if err := export.WriteCSV(f, rows); err != nil {
+		return "", err
+	}
+	return path, nil
 }
 
 // RunTUI starts the Bubble Tea program.
 func RunTUI(cfg config.Config) error {
+	m := New(cfg)
 	p := tea.NewProgram(
-		New(cfg),
+		m,
 		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(), // Enable mouse support
+		tea.WithMouseCellMotion(), // Enable mouse support; Init adds hover motion
+		tea.WithOutput(m.out),
 	)
+	// Bubble Tea quits cleanly on SIGINT/SIGTERM; a closed terminal or ssh
+	// session sends SIGHUP, which would otherwise skip the teardown
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-hup:
+			p.Quit()
+		case <-done:
+		}
+	}()
+
 	_, err := p.Run()
+	m.teardown() // covers exits that bypass quit(), e.g. a killed program
+	if err == nil {
+		err = m.fatalErr
+	}
 	return err
 }
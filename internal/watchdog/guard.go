@@ -0,0 +1,53 @@
+package watchdog
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// Guard recognizes processes that must not be signalled or reniced. Entries
+// are PIDs or name globs (path.Match) checked against the kernel comm name,
+// the executable's basename and the basename of the first command word. PID
+// 1 is always protected. A nil Guard protects only PID 1.
+type Guard struct {
+	pids  map[int]bool
+	names []string
+}
+
+// NewGuard builds a Guard from config entries; malformed globs never match.
+func NewGuard(entries []string) *Guard {
+	g := &Guard{pids: make(map[int]bool)}
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if pid, err := strconv.Atoi(e); err == nil {
+			g.pids[pid] = true
+			continue
+		}
+		g.names = append(g.names, e)
+	}
+	return g
+}
+
+// Protects reports whether p is on the list.
+func (g *Guard) Protects(p model.Process) bool {
+	if p.PID == 1 {
+		return true
+	}
+	if g == nil || p.PID <= 0 {
+		return false
+	}
+	if g.pids[p.PID] {
+		return true
+	}
+	for _, pattern := range g.names {
+		if p.MatchesName(pattern) {
+			return true
+		}
+	}
+	return false
+}
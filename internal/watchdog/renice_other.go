@@ -0,0 +1,9 @@
+//go:build !unix
+
+package watchdog
+
+import "errors"
+
+func renice(pid, nice int) error {
+	return errors.New("not supported on this platform")
+}
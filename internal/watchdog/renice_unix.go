@@ -0,0 +1,9 @@
+//go:build unix
+
+package watchdog
+
+import "syscall"
+
+func renice(pid, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
@@ -0,0 +1,141 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/system_resource_protection_script/internal/model"
+)
+
+// Watchdog lowers the priority of processes that stay above a CPU threshold
+// for several consecutive samples. Unless Enforce is set it only reports
+// what it would do.
+type Watchdog struct {
+	CPU     float64 // per-process CPU percent (100 = one core) that counts as hogging
+	Samples int     // consecutive samples over CPU before acting
+	Nice    int     // nice value applied; processes already at or above it are left alone
+	IONice  bool    // also move the process to the idle IO class (ionice -c3)
+	Enforce bool    // act; false = dry run
+	Exempt  *Guard  // processes never acted on; nil = only PID 1
+
+	over map[int]int  // consecutive samples over CPU, by PID
+	done map[int]bool // PIDs already acted on (or already nice enough)
+}
+
+// Action is one renice the watchdog applied or, in a dry run, would apply.
+type Action struct {
+	PID       int
+	Command   string
+	CPU       float64
+	Nice      int
+	IONice    bool
+	DryRun    bool
+	Err       string
+	Timestamp time.Time
+}
+
+func New(cpu float64, samples, nice int, ionice, enforce bool) *Watchdog {
+	if samples < 1 {
+		samples = 1
+	}
+	return &Watchdog{CPU: cpu, Samples: samples, Nice: nice, IONice: ionice, Enforce: enforce,
+		over: make(map[int]int), done: make(map[int]bool)}
+}
+
+// Mode is "enforcing" or "dry-run".
+func (w *Watchdog) Mode() string {
+	if w.Enforce {
+		return "enforcing"
+	}
+	return "dry-run"
+}
+
+// Observe feeds one sample's process list and returns the actions taken.
+// PIDs missing from procs are forgotten, so a hog must build its streak again
+// after dropping out of the list.
+func (w *Watchdog) Observe(procs []model.Process, now time.Time) []Action {
+	self := os.Getpid()
+	seen := make(map[int]bool, len(procs))
+	var actions []Action
+	for _, p := range procs {
+		seen[p.PID] = true
+		if p.PID == self || w.done[p.PID] || w.Exempt.Protects(p) {
+			continue
+		}
+		if p.CPU < w.CPU {
+			delete(w.over, p.PID)
+			continue
+		}
+		w.over[p.PID]++
+		if w.over[p.PID] < w.Samples {
+			continue
+		}
+		delete(w.over, p.PID)
+		w.done[p.PID] = true
+		if p.Nice >= w.Nice {
+			continue
+		}
+		actions = append(actions, w.act(p, now))
+	}
+	for pid := range w.over {
+		if !seen[pid] {
+			delete(w.over, pid)
+		}
+	}
+	for pid := range w.done {
+		if !seen[pid] {
+			delete(w.done, pid)
+		}
+	}
+	return actions
+}
+
+func (w *Watchdog) act(p model.Process, now time.Time) Action {
+	a := Action{PID: p.PID, Command: p.Command, CPU: p.CPU, Nice: w.Nice, IONice: w.IONice, DryRun: !w.Enforce, Timestamp: now}
+	if !w.Enforce {
+		return a
+	}
+	var errs []string
+	if err := renice(p.PID, w.Nice); err != nil {
+		errs = append(errs, "renice: "+err.Error())
+	}
+	if w.IONice {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		out, err := exec.CommandContext(ctx, "ionice", "-c3", "-p", fmt.Sprint(p.PID)).CombinedOutput()
+		cancel()
+		if err != nil {
+			msg := strings.TrimSpace(string(out))
+			if msg == "" {
+				msg = err.Error()
+			}
+			errs = append(errs, "ionice: "+msg)
+		}
+	}
+	a.Err = strings.Join(errs, "; ")
+	return a
+}
+
+// String describes the action for the status line and the alert hook.
+func (a Action) String() string {
+	verb := "reniced"
+	if a.DryRun {
+		verb = "would renice"
+	}
+	io := ""
+	if a.IONice {
+		io = " + ionice -c3"
+	}
+	cmd := a.Command
+	if len(cmd) > 32 {
+		cmd = cmd[:31] + "…"
+	}
+	s := fmt.Sprintf("%s %d (%s, %.0f%% CPU) to nice %d%s", verb, a.PID, cmd, a.CPU, a.Nice, io)
+	if a.Err != "" {
+		s += " failed: " + a.Err
+	}
+	return s
+}